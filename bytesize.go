@@ -0,0 +1,87 @@
+package cfg
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a size in bytes parsed from values like "512", "10MB" or "1.5GiB".
+// Decimal units (KB, MB, GB, TB, PB) are powers of 1000, binary units
+// (KiB, MiB, GiB, TiB, PiB) are powers of 1024. Units are case-insensitive; fractions
+// must come out to a whole number of bytes.
+type ByteSize int64
+
+var byteSizeUnits = map[string]int64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"pb":  1e15,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	size, err := parseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+	*b = ByteSize(size)
+	return nil
+}
+
+// parseByteSize parses a size with an optional unit suffix into bytes.
+func parseByteSize(value string) (int64, error) {
+	s := strings.TrimSpace(value)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+
+	number, unit := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+	multiplier, ok := byteSizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("unknown byte size unit %q in %q", s[i:], value)
+	}
+
+	if !strings.Contains(number, ".") {
+		n, err := strconv.ParseInt(number, 10, 64)
+		if err != nil {
+			if errors.Is(err, strconv.ErrRange) {
+				return 0, fmt.Errorf("byte size %q is out of range", value)
+			}
+			return 0, fmt.Errorf("invalid byte size %q", value)
+		}
+		if n > math.MaxInt64/multiplier {
+			return 0, fmt.Errorf("byte size %q is out of range", value)
+		}
+		return n * multiplier, nil
+	}
+
+	// Дробные значения считаются точно, без потерь float64
+	n, ok := new(big.Rat).SetString(number)
+	if !ok {
+		return 0, fmt.Errorf("invalid byte size %q", value)
+	}
+	n.Mul(n, new(big.Rat).SetInt64(multiplier))
+	if !n.IsInt() {
+		return 0, fmt.Errorf("byte size %q is not a whole number of bytes", value)
+	}
+	if !n.Num().IsInt64() {
+		return 0, fmt.Errorf("byte size %q is out of range", value)
+	}
+
+	return n.Num().Int64(), nil
+}
@@ -0,0 +1,97 @@
+package cfg
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := map[string]int64{
+		"512":    512,
+		"512B":   512,
+		"10KB":   10_000,
+		"10kb":   10_000,
+		"10MB":   10_000_000,
+		"1GB":    1_000_000_000,
+		"1KiB":   1024,
+		"10MiB":  10 << 20,
+		"1.5GiB": 3 << 29,
+		"2 TiB":  2 << 40,
+		// граница int64: 8191PiB помещается, 8192PiB = 2^63 уже нет
+		"8191PiB": 8191 << 50,
+		// целые значения разбираются без потерь float64
+		"9007199254740993":    9007199254740993,
+		"9223372036854775807": math.MaxInt64,
+		"0.5KiB":              512,
+		"1.":                  1,
+	}
+
+	for value, expected := range tests {
+		got, err := parseByteSize(value)
+		if err != nil {
+			t.Errorf("parseByteSize(%q) failed: %v", value, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("parseByteSize(%q): expected %d, got %d", value, expected, got)
+		}
+	}
+
+	for _, value := range []string{"10XB", "MB", "1.2.3MB", "99999PiB", "8192PiB", "9223372036854775808", "1.7B", "0.0001KB", "."} {
+		if _, err := parseByteSize(value); err == nil {
+			t.Errorf("Expected error for %q", value)
+		}
+	}
+}
+
+func TestByteSizeFromEnvAndYaml(t *testing.T) {
+	t.Parallel()
+
+	type SizeConfig struct {
+		MaxUpload ByteSize `yaml:"max_upload" env:"MAX_UPLOAD"`
+		Buffer    ByteSize `yaml:"buffer"`
+		Limit     int64    `yaml:"limit" env:"LIMIT" format:"bytes"`
+		Small     int8     `yaml:"small" env:"SMALL" format:"bytes"`
+		Default   uint32   `yaml:"default" format:"bytes" default:"4KiB"`
+	}
+
+	var cfg SizeConfig
+
+	err := LoadReader(&cfg, strings.NewReader("buffer: 64KiB\n"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{
+			"TEST_MAX_UPLOAD": "10MB",
+			"TEST_LIMIT":      "2GiB",
+		}),
+	)
+
+	if err != nil {
+		t.Fatalf("LoadReader failed: %v", err)
+	}
+
+	if cfg.MaxUpload != 10_000_000 {
+		t.Errorf("Expected max_upload 10000000 from env, got %d", cfg.MaxUpload)
+	}
+
+	if cfg.Buffer != 64<<10 {
+		t.Errorf("Expected buffer 65536 from YAML, got %d", cfg.Buffer)
+	}
+
+	if cfg.Limit != 2<<30 {
+		t.Errorf("Expected limit 2147483648 from env, got %d", cfg.Limit)
+	}
+
+	if cfg.Default != 4096 {
+		t.Errorf("Expected default 4096 from default tag, got %d", cfg.Default)
+	}
+
+	err = LoadReader(&cfg, strings.NewReader(""),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_SMALL": "1KB"}),
+	)
+
+	if err == nil {
+		t.Error("Expected out of range error for int8 field")
+	}
+}
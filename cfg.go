@@ -2,207 +2,2005 @@
 package cfg
 
 import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"github.com/BurntSushi/toml"
 	"gopkg.in/yaml.v3"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
+	"runtime"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 )
 
 // Action implements func for main parameters.
 type Action func(*parameters)
 
+// Option is a parameter func that can fail, e.g. on an invalid argument. Use
+// FromOption to pass it where an Action is expected.
+type Option func(*parameters) error
+
+// FromOption adapts opt to an Action. Errors of all options are collected and
+// returned by Load, wrapped in ErrInvalidOption, before anything is loaded.
+func FromOption(opt Option) Action {
+	return func(o *parameters) {
+		if err := opt(o); err != nil {
+			o.errs = append(o.errs, err)
+		}
+	}
+}
+
 type parameters struct {
-	paths     []string
+	paths              []string
+	names              []string
+	envPrefix          string
+	sliceSeparator     string
+	prefixSeparator    string
+	env                map[string]string
+	strict             bool
+	expandEnv          bool
+	merge              bool
+	format             string
+	fsys               fs.FS
+	dotEnvPaths        []string
+	dotEnv             map[string]string
+	profile            string
+	autoEnv            bool
+	requireFile        bool
+	sources            []Source
+	configPathEnv      string
+	nameEnv            string
+	validators         []func(cfg any) error
+	defaulters         []func(cfg any)
+	flags              *flag.FlagSet
+	overrides          []string
+	yamlTag            string
+	envTag             string
+	caseInsensitiveEnv bool
+	foldedEnv          map[string]string
+	ctx                context.Context
+	url                string
+	httpClient         *http.Client
+	withoutEnv         bool
+	trimSpace          bool
+	ignoreEmptyEnv     bool
+	withoutFile        bool
+	jsonTagFallback    bool
+	oneOfIgnoreCase    bool
+	secretResolver     func(key string) (string, error)
+	errs               []error
+	stageHook          func(stage string)
+	debugLog           func(string)
+	deprecationLog     func(oldName, newName string)
+	dottedKeys         bool
+	envTagVerbatim     bool
+	reset              bool
+	errorOnEmptyFile   bool
+	checkFileMode      bool
+	maxFileMode        fs.FileMode
+	strictEnv          bool
+	yamlEnvNames       bool
+	pathsEnv           string
+	xdgConfigApp       string
+	strictEnvAllow     []string
+	consumedEnv        map[string]bool
+	walking            map[reflect.Type]int
+	loadedFiles        []string
+}
+
+// WithPaths set path for find config files. Paths are directories, "-" is not read
+// as standard input here, use WithName("-") or "-" in WithConfigPathEnv for that.
+func WithPaths(paths ...string) Action {
+	return func(o *parameters) {
+		o.paths = paths
+	}
+}
+
+// WithXDGConfig append the user config directory of app to the search paths:
+// $XDG_CONFIG_HOME/<app>, falling back to ~/.config/<app>, or the platform
+// convention on macOS and Windows. It is searched after the other paths.
+func WithXDGConfig(app string) Action {
+	return func(o *parameters) {
+		o.xdgConfigApp = app
+	}
+}
+
+// WithName set config name.
+func WithName(name string) Action {
+	return func(o *parameters) {
+		o.names = []string{name}
+	}
+}
+
+// WithNameFromExecutable set config name to the executable name without extension,
+// e.g. /usr/bin/myapp loads myapp.yaml.
+func WithNameFromExecutable() Action {
+	return func(o *parameters) {
+		if len(os.Args) == 0 {
+			return
+		}
+		base := filepath.Base(os.Args[0])
+		if name := strings.TrimSuffix(base, filepath.Ext(base)); name != "" {
+			o.names = []string{name}
+		}
+	}
+}
+
+// WithNames set config names tried in order in every path, e.g. a shared "defaults"
+// and "config". Like WithName it replaces the names set before.
+func WithNames(names ...string) Action {
+	return func(o *parameters) {
+		o.names = names
+	}
+}
+
+// WithEnvPrefix set prefix for environment variables. After uppercasing and trimming
+// a trailing "_" it must consist of A-Z, 0-9 and "_", otherwise Load fails.
+func WithEnvPrefix(prefix string) Action {
+	return FromOption(func(o *parameters) error {
+		envPrefix := strings.TrimSuffix(strings.ToUpper(prefix), "_")
+		if !validEnvPrefix(envPrefix) {
+			return fmt.Errorf("env prefix %q must match [A-Z0-9_]+", prefix)
+		}
+		o.envPrefix = envPrefix
+		return nil
+	})
+}
+
+func validEnvPrefix(prefix string) bool {
+	for _, r := range prefix {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// WithSliceSeparator set separator for slice values in environment variables.
+func WithSliceSeparator(separator string) Action {
+	return func(o *parameters) {
+		o.sliceSeparator = separator
+	}
+}
+
+// WithPrefixSeparator set separator joining the env prefix and names, nested
+// WithAutoEnv segments, map keys and slice indices, e.g. "__" for APP__SERVER__PORT.
+// Default "_". Separators inside env tags and CamelCase field names stay "_".
+func WithPrefixSeparator(separator string) Action {
+	return func(o *parameters) {
+		o.prefixSeparator = separator
+	}
+}
+
+// WithEnv set environment variables used instead of the process environment.
+func WithEnv(env map[string]string) Action {
+	return func(o *parameters) {
+		o.env = env
+	}
+}
+
+// WithStrict enable errors on unknown keys in config file.
+func WithStrict() Action {
+	return func(o *parameters) {
+		o.strict = true
+	}
+}
+
+// WithExpandEnv enable ${VAR} expansion in string values of config file.
+func WithExpandEnv() Action {
+	return func(o *parameters) {
+		o.expandEnv = true
+	}
+}
+
+// WithMerge enable loading of every found config file instead of the first one.
+// Files are applied in search order (paths order, then names order, then formats order),
+// so later files override earlier ones field by field.
+func WithMerge() Action {
+	return func(o *parameters) {
+		o.merge = true
+	}
+}
+
+// WithFormat set config format ("yaml", "json", "toml", "ini" or "properties"). For LoadReader it selects the decoder,
+// for Load it limits probed files to the extensions of the format.
+func WithFormat(format string) Action {
+	return FromOption(func(o *parameters) error {
+		name := strings.ToLower(format)
+		if _, ok := formatByName(name); !ok {
+			return fmt.Errorf("unsupported format %q", format)
+		}
+		o.format = name
+		return nil
+	})
+}
+
+// WithFS set filesystem (e.g. embed.FS) searched for config files before the OS filesystem.
+func WithFS(fsys fs.FS) Action {
+	return func(o *parameters) {
+		o.fsys = fsys
+	}
+}
+
+// WithDotEnv set .env files whose variables are used for env overrides.
+// Process environment takes precedence over .env files, later files override earlier ones.
+// Default path is ".env".
+func WithDotEnv(paths ...string) Action {
+	return func(o *parameters) {
+		// Action может выполняться многократно (Loader), поэтому paths не изменяем
+		p := paths
+		if len(p) == 0 {
+			p = []string{".env"}
+		}
+		o.dotEnvPaths = p
+	}
+}
+
+// WithProfile set config profile. For every config file <name>.<ext> the profile file
+// <name>.<profile>.<ext> is searched right after it, use WithMerge to layer it on top.
+func WithProfile(profile string) Action {
+	return func(o *parameters) {
+		o.profile = profile
+	}
+}
+
+// WithEnvTagVerbatim use env tags exactly as written instead of uppercasing them,
+// e.g. env:"http_proxy" reads http_proxy. The prefix is not changed.
+func WithEnvTagVerbatim() Action {
+	return func(o *parameters) {
+		o.envTagVerbatim = true
+	}
+}
+
+// WithAutoEnv enable env names derived from the field path for fields without env tag,
+// e.g. Server.MaxConns reads APP_SERVER_MAX_CONNS.
+func WithAutoEnv() Action {
+	return func(o *parameters) {
+		o.autoEnv = true
+	}
+}
+
+// WithYamlEnvNames build WithAutoEnv names from yaml tag names instead of Go field
+// names, so env names follow the file keys: yaml:"max-conns" reads APP_SERVER_MAX_CONNS.
+func WithYamlEnvNames() Action {
+	return func(o *parameters) {
+		o.yamlEnvNames = true
+	}
+}
+
+// WithRequireFile enable error when no config file is found.
+func WithRequireFile() Action {
+	return func(o *parameters) {
+		o.requireFile = true
+	}
+}
+
+// Source describes one layer of config files: a name searched in paths.
+type Source struct {
+	// Name is the config file name without extension, defaults to WithName.
+	Name string
+	// Paths are the directories to search, default to WithPaths.
+	Paths []string
+	// Required makes Load fail when the source has no file.
+	Required bool
+}
+
+// WithSource add a config source. Sources are loaded in the order they are
+// added, each on top of the previous one, and replace the default name/paths
+// search. Only required sources fail Load when missing.
+func WithSource(source Source) Action {
+	return func(o *parameters) {
+		o.sources = append(o.sources, source)
+	}
+}
+
+// WithErrorOnEmptyFile enable error when a found config file is empty or holds only
+// whitespace, e.g. truncated by a failed deployment. By default such a file changes nothing.
+func WithErrorOnEmptyFile() Action {
+	return func(o *parameters) {
+		o.errorOnEmptyFile = true
+	}
+}
+
+// WithMaxFileMode reject config files with permissions more permissive than mode,
+// e.g. 0600 for files holding secrets, like SSH rejects world-readable keys. It covers
+// every file read from disk: config files, includes, .env files and _FILE targets.
+// The check is skipped on Windows and for files read from WithFS.
+func WithMaxFileMode(mode fs.FileMode) Action {
+	return func(o *parameters) {
+		o.checkFileMode = true
+		o.maxFileMode = mode.Perm()
+	}
+}
+
+// WithNameEnv set environment variable holding the config name. When it is set,
+// its value replaces the name given with WithName or WithNames.
+func WithNameEnv(name string) Action {
+	return func(o *parameters) {
+		o.nameEnv = name
+	}
+}
+
+// WithPathsFromEnv set environment variable holding the search paths, separated by
+// os.PathListSeparator like PATH. When it is set and non-empty, it replaces WithPaths.
+func WithPathsFromEnv(name string) Action {
+	return func(o *parameters) {
+		o.pathsEnv = name
+	}
+}
+
+// WithConfigPathEnv set environment variable holding the config file path.
+// When it is set, exactly that file is loaded instead of searching paths.
+func WithConfigPathEnv(name string) Action {
+	return func(o *parameters) {
+		o.configPathEnv = name
+	}
+}
+
+// WithValidator add validation func called after loading. Validators run in order,
+// the first error stops loading.
+func WithValidator(validator func(cfg any) error) Action {
+	return func(o *parameters) {
+		o.validators = append(o.validators, validator)
+	}
+}
+
+// WithFlags override fields tagged flag:"name" with flags explicitly set in fs.
+// The flag set must be parsed before loading, flags left unset change nothing.
+func WithFlags(fs *flag.FlagSet) Action {
+	return func(o *parameters) {
+		o.flags = fs
+	}
+}
+
+// WithTagName set struct tags used instead of "yaml" and "env", e.g. for structs
+// tagged for another library. The yaml tag names keys of YAML files, JSON and TOML
+// keep their own tags. Empty names keep the defaults.
+func WithTagName(yaml, env string) Action {
+	return func(o *parameters) {
+		if yaml != "" {
+			o.yamlTag = yaml
+		}
+		if env != "" {
+			o.envTag = env
+		}
+	}
+}
+
+// WithDottedKeys expand top-level YAML keys that are dotted paths, e.g.
+// server.port: 9090, into nested keys. Useful for generated flat override files.
+func WithDottedKeys() Action {
+	return func(o *parameters) {
+		o.dottedKeys = true
+	}
+}
+
+// WithJSONTagFallback match YAML keys with the json tag of fields without a yaml
+// tag, so structs shared with JSON APIs need no duplicate tags.
+func WithJSONTagFallback() Action {
+	return func(o *parameters) {
+		o.jsonTagFallback = true
+	}
+}
+
+// WithOneOfIgnoreCase compare values with oneof tags ignoring case.
+func WithOneOfIgnoreCase() Action {
+	return func(o *parameters) {
+		o.oneOfIgnoreCase = true
+	}
+}
+
+// WithCaseInsensitiveEnv match env variables ignoring case when the exact name is
+// not set, e.g. Server_Port for SERVER_PORT. The whole environment is read once per
+// load to build the lookup table.
+func WithCaseInsensitiveEnv() Action {
+	return func(o *parameters) {
+		o.caseInsensitiveEnv = true
+	}
+}
+
+// WithURL load the config from an http(s) URL instead of searching config files.
+// The format comes from WithFormat, the Content-Type header or the URL extension,
+// non-200 responses are errors. Env overrides apply as usual.
+func WithURL(url string) Action {
+	return func(o *parameters) {
+		o.url = url
+	}
+}
+
+// WithHTTPClient set the client used by WithURL. Default client has a 10s timeout.
+func WithHTTPClient(client *http.Client) Action {
+	return func(o *parameters) {
+		o.httpClient = client
+	}
+}
+
+// WithStrictEnv enable error for env variables with the env prefix that no field
+// reads, e.g. a typo like APP_SERVER_PROT. Variables in allow are exempt, as are the
+// ones named by WithConfigPathEnv, WithNameEnv and WithPathsFromEnv.
+func WithStrictEnv(allow ...string) Action {
+	return func(o *parameters) {
+		o.strictEnv = true
+		o.strictEnvAllow = append(o.strictEnvAllow, allow...)
+	}
+}
+
+// WithDebugLog set a function receiving every env lookup decision: variables
+// found, missing or read from a _FILE, variables with the env prefix that no field
+// uses, and env names shared by several fields. Values are never logged.
+func WithDebugLog(log func(msg string)) Action {
+	return func(o *parameters) {
+		o.debugLog = log
+	}
+}
+
+// WithDeprecationLog set a function called when a field is set from a deprecated
+// name listed in its envAlias tag, with the full alias and primary variable names.
+func WithDeprecationLog(log func(oldName, newName string)) Action {
+	return func(o *parameters) {
+		o.deprecationLog = log
+	}
+}
+
+// WithTrimSpace trim leading and trailing whitespace from env values, e.g. a
+// newline left by file-based injection.
+func WithTrimSpace() Action {
+	return func(o *parameters) {
+		o.trimSpace = true
+	}
+}
+
+// WithIgnoreEmptyEnv ignore env variables that are set but empty, so APP_NAME= keeps
+// the value from the file. It applies to every field, see also env:"NAME,skipempty".
+func WithIgnoreEmptyEnv() Action {
+	return func(o *parameters) {
+		o.ignoreEmptyEnv = true
+	}
+}
+
+// WithoutEnv skip env overrides, only defaults and config files are loaded.
+func WithoutEnv() Action {
+	return func(o *parameters) {
+		o.withoutEnv = true
+	}
+}
+
+// WithoutFile skip searching config files (and WithURL), the config comes only
+// from defaults and env. Required fields are still validated.
+func WithoutFile() Action {
+	return func(o *parameters) {
+		o.withoutFile = true
+	}
+}
+
+// WithSecretCommand resolve string values of the form secret://<key> with resolver,
+// e.g. by running vault or sops. Resolution runs after env overrides and before
+// validation, so both file and env values can reference secrets.
+func WithSecretCommand(resolver func(key string) (string, error)) Action {
+	return func(o *parameters) {
+		o.secretResolver = resolver
+	}
+}
+
+// WithDefaulter add func filling derived defaults, e.g. Addr from Host and Port.
+// Defaulters run in order after files, env and flags are applied and before
+// validation, so derived values are validated too. They should only set zero fields.
+func WithDefaulter(defaulter func(cfg any)) Action {
+	return func(o *parameters) {
+		o.defaulters = append(o.defaulters, defaulter)
+	}
+}
+
+// WithOverrides set fields from key=value pairs with dotted file keys, e.g.
+// server.port=9090. Overrides take precedence over every other source.
+func WithOverrides(overrides []string) Action {
+	return func(o *parameters) {
+		o.overrides = append(o.overrides, overrides...)
+	}
+}
+
+// WithReset zero cfg before loading, so reusing a struct for several loads gives
+// the same result as loading into a fresh one. With WithMerge it is reset once and
+// the files are then merged as usual.
+func WithReset() Action {
+	return func(o *parameters) {
+		o.reset = true
+	}
+}
+
+// MustLoad downloads the configuration or panics.
+func MustLoad(cfg any, paramsAction ...Action) {
+	if err := Load(cfg, paramsAction...); err != nil {
+		panic("cfg: failed to load config: " + err.Error())
+	}
+}
+
+// LoadInto downloads the configuration into a new value of type T.
+func LoadInto[T any](paramsActions ...Action) (T, error) {
+	var cfg T
+	err := Load(&cfg, paramsActions...)
+	return cfg, err
+}
+
+// MustLoadInto downloads the configuration into a new value of type T or panics.
+func MustLoadInto[T any](paramsActions ...Action) T {
+	var cfg T
+	MustLoad(&cfg, paramsActions...)
+	return cfg
+}
+
+// Load downloads the configuration
+func Load(cfg any, paramsActions ...Action) error {
+	return LoadContext(context.Background(), cfg, paramsActions...)
+}
+
+// LoadContext downloads the configuration, stopping with ctx.Err() once ctx is done.
+func LoadContext(ctx context.Context, cfg any, paramsActions ...Action) error {
+	_, err := load(ctx, cfg, loadFromFile, paramsActions)
+	return err
+}
+
+// LoadReader downloads the configuration from reader instead of searching config files.
+// The format is detected from the content (yaml, then json, then toml) unless set with WithFormat.
+func LoadReader(cfg any, r io.Reader, paramsActions ...Action) error {
+	_, err := load(context.Background(), cfg, func(cfg any, p *parameters) error {
+		return loadFromReader(cfg, r, p)
+	}, paramsActions)
+	return err
+}
+
+// load runs the loading pipeline and returns the resolved parameters.
+func load(ctx context.Context, cfg any, source func(cfg any, p *parameters) error, paramsActions []Action) (*parameters, error) {
+	if err := validateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	p, err := newParameters(paramsActions)
+	if err != nil {
+		return nil, err
+	}
+	p.ctx = ctx
+
+	// zero the destination once, so a reused struct keeps no stale slice or map entries
+	if p.reset {
+		reflect.ValueOf(cfg).Elem().SetZero()
+	}
+
+	if err := loadDotEnv(p); err != nil {
+		return nil, fmt.Errorf("load dotenv: %w", err)
+	}
+
+	// first apply defaults from struct tags
+	if err := loadDefaults(cfg, p); err != nil {
+		return nil, fmt.Errorf("load defaults: %w", err)
+	}
+	p.afterStage("default")
+
+	// then load from config source
+	if err := source(cfg, p); err != nil {
+		return nil, fmt.Errorf("unload config file: %w", err)
+	}
+
+	// then expand ${VAR} references in string values
+	if p.expandEnv {
+		expandStringValues(reflect.ValueOf(cfg).Elem(), p)
+	}
+	p.afterStage("file")
+
+	// then override with environment variables
+	if !p.withoutEnv {
+		if err := loadFromEnv(cfg, p); err != nil {
+			return nil, fmt.Errorf("load env: %w", err)
+		}
+
+		if p.strictEnv {
+			if err := checkStrictEnv(cfg, p); err != nil {
+				return nil, fmt.Errorf("load env: %w", err)
+			}
+		}
+	}
+	p.afterStage("env")
+
+	// then override with command-line flags
+	if err := loadFromFlags(cfg, p); err != nil {
+		return nil, fmt.Errorf("load flags: %w", err)
+	}
+	p.afterStage("flag")
+
+	// then apply key=value overrides
+	if err := loadFromOverrides(cfg, p); err != nil {
+		return nil, fmt.Errorf("apply overrides: %w", err)
+	}
+	p.afterStage("override")
+
+	// then resolve secret:// references
+	if p.secretResolver != nil {
+		if err := resolveSecrets(reflect.ValueOf(cfg).Elem(), p, ""); err != nil {
+			return nil, fmt.Errorf("resolve secrets: %w", err)
+		}
+	}
+	p.afterStage("secret")
+
+	// then fill derived defaults
+	for _, defaulter := range p.defaulters {
+		defaulter(cfg)
+	}
+	p.afterStage("defaulter")
+
+	// finally validate config
+	if err := validateTags(cfg, p); err != nil {
+		return nil, fmt.Errorf("validate config: %w", err)
+	}
+
+	for _, validator := range p.validators {
+		if err := validator(cfg); err != nil {
+			return nil, fmt.Errorf("validate config: %w", err)
+		}
+	}
+
+	return p, nil
+}
+
+// afterStage reports a finished loading stage to Explain.
+func (p *parameters) afterStage(stage string) {
+	if p.stageHook != nil {
+		p.stageHook(stage)
+	}
+}
+
+func validateConfig(cfg any) error {
+	if cfg == nil {
+		return ErrNilConfig
+	}
+
+	v := reflect.ValueOf(cfg)
+	if v.Kind() == reflect.Struct {
+		return fmt.Errorf("%w, got %s: did you mean to pass a pointer (&cfg)?", ErrNotPointer, v.Type())
+	}
+
+	if v.Kind() != reflect.Ptr {
+		return ErrNotPointer
+	}
+
+	if v.IsNil() {
+		return fmt.Errorf("config pointer: %w", ErrNilConfig)
+	}
+
+	if v.Elem().Kind() != reflect.Struct {
+		return ErrNotPointer
+	}
+
+	return nil
+}
+
+// lookupEnv returns environment variable from WithEnv map or from the process environment,
+// falling back to variables from .env files.
+func (p *parameters) lookupEnv(key string) (string, bool) {
+	var value string
+	var ok bool
+	if p.env != nil {
+		value, ok = p.env[key]
+	} else {
+		value, ok = os.LookupEnv(key)
+	}
+
+	if !ok && p.dotEnv != nil {
+		value, ok = p.dotEnv[key]
+	}
+
+	if !ok && p.caseInsensitiveEnv {
+		if p.foldedEnv == nil {
+			p.foldedEnv = make(map[string]string)
+			for key, value := range p.environ() {
+				p.foldedEnv[strings.ToUpper(key)] = value
+			}
+		}
+		value, ok = p.foldedEnv[strings.ToUpper(key)]
+	}
+
+	return value, ok
+}
+
+// environ returns all environment variables visible to lookupEnv.
+func (p *parameters) environ() map[string]string {
+	env := make(map[string]string)
+	for key, value := range p.dotEnv {
+		env[key] = value
+	}
+
+	if p.env != nil {
+		for key, value := range p.env {
+			env[key] = value
+		}
+		return env
+	}
+
+	for _, kv := range os.Environ() {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			env[key] = value
+		}
+	}
+
+	return env
+}
+
+// newParameters applies actions to the default parameters and returns the errors
+// recorded by them.
+func newParameters(paramsActions []Action) (*parameters, error) {
+	p := defaultParameters()
+	for _, paramAction := range paramsActions {
+		paramAction(p)
+	}
+
+	if len(p.errs) > 0 {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidOption, errors.Join(p.errs...))
+	}
+	return p, nil
+}
+
+func defaultParameters() *parameters {
+	return &parameters{
+		paths:           []string{".", "./config"},
+		names:           []string{"config"},
+		envPrefix:       "APP",
+		sliceSeparator:  ",",
+		prefixSeparator: "_",
+		yamlTag:         "yaml",
+		envTag:          "env",
+	}
+}
+
+// format describes a supported config file format.
+type format struct {
+	ext       string
 	name      string
-	envPrefix string
+	unmarshal func(data []byte, v any, params *parameters) error
+}
+
+// formats lists supported config file formats in probing priority order.
+var formats = []format{
+	{ext: ".yaml", name: "yaml", unmarshal: unmarshalYaml},
+	{ext: ".yml", name: "yaml", unmarshal: unmarshalYaml},
+	{ext: ".json", name: "json", unmarshal: unmarshalJson},
+	{ext: ".toml", name: "toml", unmarshal: unmarshalToml},
+	{ext: ".ini", name: "ini", unmarshal: unmarshalIni},
+	{ext: ".properties", name: "properties", unmarshal: unmarshalIni},
+}
+
+func unmarshalYaml(data []byte, v any, params *parameters) error {
+	if params.dottedKeys {
+		expanded, err := expandDottedKeys(data)
+		if err != nil {
+			return err
+		}
+		data = expanded
+	}
+
+	// секции вариантов извлекаются до переименования, их ключи ищутся по WithTagName
+	if hasVariants(reflect.TypeOf(v)) {
+		rest, err := decodeVariants(data, v, params)
+		if err != nil {
+			return err
+		}
+		data = rest
+	}
+
+	if params.yamlTag != "yaml" || params.jsonTagFallback {
+		renamed, err := renameYamlKeys(data, reflect.TypeOf(v), params)
+		if err != nil {
+			return err
+		}
+		data = renamed
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(params.strict)
+	if err := decoder.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+func unmarshalJson(data []byte, v any, params *parameters) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if params.strict {
+		decoder.DisallowUnknownFields()
+	}
+	return decoder.Decode(v)
+}
+
+func unmarshalToml(data []byte, v any, params *parameters) error {
+	meta, err := toml.Decode(string(data), v)
+	if err != nil {
+		return err
+	}
+
+	if undecoded := meta.Undecoded(); params.strict && len(undecoded) > 0 {
+		return fmt.Errorf("unknown keys: %v", undecoded)
+	}
+
+	return nil
+}
+
+// candidate is a config file that may be loaded.
+type candidate struct {
+	fullName string
+	format   format
+}
+
+// candidates returns config files in search order: paths, then names
+// (base name, then profile name), then formats.
+func candidates(dirs []string, parameters *parameters) []candidate {
+	var names []string
+	for _, name := range parameters.names {
+		names = append(names, name)
+		if parameters.profile != "" {
+			names = append(names, name+"."+parameters.profile)
+		}
+	}
+
+	var result []candidate
+	for _, dir := range dirs {
+		for _, name := range names {
+			for _, f := range formats {
+				if parameters.format != "" && parameters.format != f.name {
+					continue
+				}
+				result = append(result, candidate{fullName: filepath.Join(dir, name+f.ext), format: f})
+			}
+		}
+	}
+
+	return result
+}
+
+func loadFromFile(cfg any, parameters *parameters) error {
+	if parameters.withoutFile {
+		return nil
+	}
+
+	if parameters.nameEnv != "" {
+		if name, ok := parameters.lookupEnv(parameters.nameEnv); ok && name != "" {
+			parameters.names = []string{name}
+		}
+	}
+
+	if parameters.pathsEnv != "" {
+		if value, ok := parameters.lookupEnv(parameters.pathsEnv); ok && value != "" {
+			parameters.paths = splitPathList(value)
+		}
+	}
+
+	if parameters.configPathEnv != "" {
+		if fullName, ok := parameters.lookupEnv(parameters.configPathEnv); ok && fullName != "" {
+			return loadConfigPath(cfg, fullName, parameters)
+		}
+	}
+
+	if parameters.url != "" {
+		return loadFromURL(cfg, parameters)
+	}
+
+	if len(parameters.sources) > 0 {
+		return loadSources(cfg, parameters)
+	}
+
+	return searchFiles(cfg, parameters)
+}
+
+// splitPathList splits a list of paths joined by os.PathListSeparator, skipping empty entries.
+func splitPathList(value string) []string {
+	var paths []string
+	for _, p := range filepath.SplitList(value) {
+		if p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// loadSources loads every source in order, each one on top of the previous.
+// A source without a name or paths falls back to WithName/WithPaths.
+func loadSources(cfg any, parameters *parameters) error {
+	for _, source := range parameters.sources {
+		sourceParams := *parameters
+		sourceParams.requireFile = source.Required
+		if source.Name != "" {
+			sourceParams.names = []string{source.Name}
+		}
+		if len(source.Paths) > 0 {
+			sourceParams.paths = source.Paths
+		}
+
+		err := searchFiles(cfg, &sourceParams)
+		parameters.loadedFiles = sourceParams.loadedFiles
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// searchFiles loads the first config file found in the search paths,
+// or all of them with WithMerge.
+func searchFiles(cfg any, parameters *parameters) error {
+	if slices.Contains(parameters.names, stdinName) {
+		return loadStdin(cfg, parameters)
+	}
+
+	fileSystems := []fileSystem{osFileSystem{}}
+	if parameters.fsys != nil {
+		fileSystems = []fileSystem{fsFileSystem{fsys: parameters.fsys}, osFileSystem{}}
+	}
+
+	var searched []string
+	found := false
+	paths, err := expandSearchPaths(parameters)
+	if err != nil {
+		return err
+	}
+
+	for _, fsys := range fileSystems {
+		dirs, err := expandPaths(fsys, paths)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range candidates(dirs, parameters) {
+			if err := parameters.ctx.Err(); err != nil {
+				return err
+			}
+
+			searched = append(searched, c.fullName)
+			loaded, err := loadFile(cfg, c.fullName, c.format, fsys, parameters)
+			if err != nil {
+				return err
+			}
+
+			if loaded && !parameters.merge {
+				return nil
+			}
+			found = found || loaded
+		}
+	}
+
+	if !found && parameters.requireFile {
+		return fmt.Errorf("%w: %s in paths %v, tried: %s", ErrConfigNotFound,
+			quoteNames(parameters.names), parameters.paths, strings.Join(searched, ", "))
+	}
+
+	return nil
+}
+
+func quoteNames(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = strconv.Quote(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// loadConfigPath loads exactly one file. The format is taken from WithFormat,
+// then from the file extension, and defaults to yaml.
+func loadConfigPath(cfg any, fullName string, parameters *parameters) error {
+	if fullName == stdinName {
+		return loadStdin(cfg, parameters)
+	}
+
+	f, err := formatForFile(fullName, parameters)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(fullName)
+	if err != nil {
+		return fmt.Errorf("unread file %s: %w", fullName, err)
+	}
+
+	if err := checkFileMode(fullName, parameters); err != nil {
+		return err
+	}
+
+	if err := checkEmptyFile(data, fullName, parameters); err != nil {
+		return err
+	}
+
+	var included []string
+	if f.name == "yaml" {
+		data, included, err = resolveIncludes(data, fullName, osFileSystem{}, parameters)
+		if err != nil {
+			return &ParseError{File: fullName, Format: f.name, Err: err}
+		}
+	}
+
+	if name, err := unmarshalData(f, data, cfg, parameters); err != nil {
+		return &ParseError{File: fullName, Format: name, Err: err}
+	}
+
+	parameters.loadedFiles = append(parameters.loadedFiles, fullName)
+	parameters.loadedFiles = append(parameters.loadedFiles, included...)
+	return nil
+}
+
+// formatForFile picks the format from WithFormat, then the extension of fullName.
+// Without either the format is detected from the content.
+func formatForFile(fullName string, parameters *parameters) (format, error) {
+	if parameters.format != "" {
+		f, ok := formatByName(parameters.format)
+		if !ok {
+			return format{}, fmt.Errorf("unsupported format %q", parameters.format)
+		}
+		return f, nil
+	}
+
+	ext := strings.ToLower(filepath.Ext(fullName))
+	for _, f := range formats {
+		if f.ext == ext {
+			return f, nil
+		}
+	}
+
+	return autoFormat, nil
+}
+
+func loadFromReader(cfg any, r io.Reader, parameters *parameters) error {
+	f, err := formatForFile("", parameters)
+	if err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("unread reader: %w", err)
+	}
+
+	if name, err := unmarshalData(f, data, cfg, parameters); err != nil {
+		return &ParseError{Format: name, Err: err}
+	}
+
+	return nil
+}
+
+func formatByName(name string) (format, bool) {
+	for _, f := range formats {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return format{}, false
+}
+
+// loadFile decodes the file into cfg and reports whether the file exists.
+func loadFile(cfg any, fullName string, f format, fsys fileSystem, parameters *parameters) (bool, error) {
+	data, err := fsys.ReadFile(fullName)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("unread file %s: %w", fullName, err)
+	}
+
+	if _, ok := fsys.(osFileSystem); ok {
+		if err := checkFileMode(fullName, parameters); err != nil {
+			return false, err
+		}
+	}
+
+	if err := checkEmptyFile(data, fullName, parameters); err != nil {
+		return false, err
+	}
+
+	var included []string
+	if f.name == "yaml" {
+		data, included, err = resolveIncludes(data, fullName, fsys, parameters)
+		if err != nil {
+			return false, &ParseError{File: fullName, Format: f.name, Err: err}
+		}
+	}
+
+	if err := f.unmarshal(data, cfg, parameters); err != nil {
+		return false, &ParseError{File: fullName, Format: f.name, Err: err}
+	}
+
+	if _, ok := fsys.(osFileSystem); ok {
+		parameters.loadedFiles = append(parameters.loadedFiles, fullName)
+		parameters.loadedFiles = append(parameters.loadedFiles, included...)
+	}
+
+	return true, nil
+}
+
+// checkFileMode fails with WithMaxFileMode when fullName grants permissions beyond
+// the allowed mode.
+func checkFileMode(fullName string, parameters *parameters) error {
+	if !parameters.checkFileMode || runtime.GOOS == "windows" {
+		return nil
+	}
+
+	info, err := os.Stat(fullName)
+	if err != nil {
+		return fmt.Errorf("stat file %s: %w", fullName, err)
+	}
+
+	if perm := info.Mode().Perm(); perm&^parameters.maxFileMode != 0 {
+		return fmt.Errorf("%w: %s has mode %04o, allowed at most %04o", ErrFileMode, fullName, perm, parameters.maxFileMode)
+	}
+	return nil
+}
+
+// checkEmptyFile fails with WithErrorOnEmptyFile when data is empty or whitespace only.
+func checkEmptyFile(data []byte, fullName string, parameters *parameters) error {
+	if parameters.errorOnEmptyFile && len(bytes.TrimSpace(data)) == 0 {
+		return fmt.Errorf("%w: %s", ErrEmptyFile, fullName)
+	}
+	return nil
+}
+
+// expandStringValues expands $VAR and ${VAR} in string values, including string map
+// values and strings held in interface fields. Variables are looked up with envPrefix
+// first and then without it, unknown variables expand to empty string and $$ is a literal $.
+func expandStringValues(v reflect.Value, params *parameters) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			expandStringValues(v.Elem(), params)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Field(i); field.CanSet() || v.Type().Field(i).Anonymous {
+				expandStringValues(field, params)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			expandStringValues(v.Index(i), params)
+		}
+	case reflect.Map:
+		if !v.CanSet() {
+			return
+		}
+		elem := v.Type().Elem()
+		iter := v.MapRange()
+		for iter.Next() {
+			switch elem.Kind() {
+			case reflect.String:
+				value := os.Expand(iter.Value().String(), params.expandMapping)
+				v.SetMapIndex(iter.Key(), reflect.ValueOf(value).Convert(elem))
+			case reflect.Interface:
+				if !iter.Value().IsNil() {
+					v.SetMapIndex(iter.Key(), reflect.ValueOf(expandAny(iter.Value().Interface(), params)))
+				}
+			}
+		}
+	case reflect.Interface:
+		if v.CanSet() && !v.IsNil() {
+			v.Set(reflect.ValueOf(expandAny(v.Interface(), params)))
+		}
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(os.Expand(v.String(), params.expandMapping))
+		}
+	}
+}
+
+// expandAny expands strings in a value decoded into any: plain strings and the
+// values of nested maps and slices.
+func expandAny(value any, params *parameters) any {
+	switch value := value.(type) {
+	case string:
+		return os.Expand(value, params.expandMapping)
+	case map[string]any:
+		for key, item := range value {
+			value[key] = expandAny(item, params)
+		}
+	case []any:
+		for i, item := range value {
+			value[i] = expandAny(item, params)
+		}
+	}
+	return value
+}
+
+func (p *parameters) expandMapping(name string) string {
+	if name == "$" {
+		return "$"
+	}
+	if p.envPrefix != "" {
+		if value, ok := p.lookupEnv(p.envPrefix + p.prefixSeparator + name); ok {
+			return value
+		}
+	}
+	value, _ := p.lookupEnv(name)
+	return value
+}
+
+func loadDefaults(cfg any, params *parameters) error {
+	v := reflect.ValueOf(cfg).Elem()
+	return loadStructDefaults(v, params)
+}
+
+func loadStructDefaults(v reflect.Value, params *parameters) error {
+	t := v.Type()
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		structField := t.Field(i)
+
+		if structField.Anonymous && field.Kind() == reflect.Struct {
+			if err := loadStructDefaults(field, params); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !field.CanSet() {
+			continue
+		}
+
+		if isNestedStruct(field) {
+			if err := loadStructDefaults(field, params); err != nil {
+				return err
+			}
+			continue
+		}
+
+		defaultValue, ok := structField.Tag.Lookup("default")
+		if !ok || !field.IsZero() {
+			continue
+		}
+
+		if err := setStructField(field, structField, defaultValue, params); err != nil {
+			return fmt.Errorf("set field %s from default %q: %w",
+				structField.Name, defaultValue, err)
+		}
+	}
+
+	return nil
+}
+
+func loadFromEnv(cfg any, params *parameters) error {
+	if params.debugLog != nil {
+		params.consumedEnv = make(map[string]bool)
+	}
+
+	v := reflect.ValueOf(cfg).Elem()
+	if _, err := loadStructFromEnv(v, params, "", ""); err != nil {
+		return err
+	}
+
+	if params.debugLog != nil {
+		logEnvUsage(cfg, params)
+	}
+	return nil
+}
+
+// loadStructFromEnv overrides struct fields from environment variables
+// and reports whether any field was set.
+func loadStructFromEnv(v reflect.Value, params *parameters, path, envPath string) (bool, error) {
+	t := v.Type()
+	set := false
+	defer params.enterWalk(t)()
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		structField := t.Field(i)
+
+		// Встроенные (anonymous) структуры обходим с тем же префиксом, как yaml ",inline".
+		// Поля неэкспортируемой встроенной структуры тоже доступны для записи.
+		if structField.Anonymous && field.Kind() == reflect.Struct {
+			fieldSet, err := loadStructFromEnv(field, params, path, envPath)
+			if err != nil {
+				return false, err
+			}
+			set = set || fieldSet
+			continue
+		}
+
+		if !field.CanSet() {
+			continue
+		}
+
+		// env:"-" исключает поле (и вложенные поля) из переопределения
+		if structField.Tag.Get(params.envTag) == "-" {
+			continue
+		}
+
+		fieldPath := joinFieldPath(path, structField.Name)
+		fieldEnvPath := joinFieldPath(envPath, envPathSegment(structField, params))
+
+		// Рекурсивно обрабатываем вложенные структуры
+		if isNestedStruct(field) {
+			fieldSet, err := loadStructFromEnv(field, params, fieldPath, fieldEnvPath)
+			if err != nil {
+				return false, err
+			}
+			set = set || fieldSet
+			continue
+		}
+
+		if isStructPtrChain(field.Type()) {
+			fieldSet, err := loadStructPtrFromEnv(field, params, fieldPath, fieldEnvPath)
+			if err != nil {
+				return false, err
+			}
+			set = set || fieldSet
+			continue
+		}
+
+		envVar := getEnvVarName(structField, params, fieldEnvPath)
+
+		if isStructSlice(field) {
+			if envVar == "" {
+				continue
+			}
+			fieldSet, err := loadSliceElemsFromEnv(field, envVar, params)
+			if err != nil {
+				return false, fmt.Errorf("field %s: %w", fieldPath, err)
+			}
+			set = set || fieldSet
+			continue
+		}
+
+		if field.Kind() == reflect.Map && envVar != "" {
+			fieldSet, err := loadMapFromEnv(field, structField, envVar, params)
+			if err != nil {
+				return false, &EnvError{Field: fieldPath, Var: envVar + params.prefixSeparator + "*", Err: err}
+			}
+			set = set || fieldSet
+			continue
+		}
+
+		if envVar == "" {
+			continue
+		}
+
+		// env:"DISABLE_X,invert" записывает в bool поле отрицание значения переменной
+		_, envOpts, _ := strings.Cut(structField.Tag.Get(params.envTag), ",")
+		invert := hasTagOption(envOpts, "invert")
+		if invert && field.Kind() != reflect.Bool {
+			return false, &EnvError{Field: fieldPath, Var: envVar, Err: fmt.Errorf("invert option requires a bool field, got %s", field.Type())}
+		}
+
+		envValue, exists, err := resolveEnv(envVar, params)
+		if err != nil {
+			return false, &EnvError{Field: fieldPath, Var: envVar, Err: err}
+		}
+
+		// envAlias:"OLD_NAME" читается, только если основная переменная не задана
+		if !exists {
+			envValue, exists, err = resolveEnvAlias(structField, envVar, params)
+			if err != nil {
+				return false, &EnvError{Field: fieldPath, Var: envVar, Err: err}
+			}
+		}
+
+		// env:"NAME,skipempty" не даёт пустой переменной затереть значение из файла
+		if exists && envValue == "" && skipEmptyEnv(structField, params) {
+			params.debugf("env %s: empty, skipped", envVar)
+			exists = false
+		}
+
+		if exists {
+			if err := setStructField(field, structField, envValue, params); err != nil {
+				return false, &EnvError{Field: fieldPath, Var: envVar, Err: err}
+			}
+			if invert {
+				field.SetBool(!field.Bool())
+			}
+			set = true
+		}
+	}
+
+	return set, nil
 }
 
-// WithPaths set path for find config files.
-func WithPaths(paths ...string) Action {
-	return func(o *parameters) {
-		o.paths = paths
+// isStructSlice reports whether field is a slice or array of structs, or of pointers
+// to them, walked field by field.
+func isStructSlice(field reflect.Value) bool {
+	if field.Kind() != reflect.Slice && field.Kind() != reflect.Array {
+		return false
 	}
+	elem := field.Type().Elem()
+	return isStructPtrChain(elem) || elem.Kind() == reflect.Struct && !isValueType(elem)
 }
 
-// WithName set config name.
-func WithName(name string) Action {
-	return func(o *parameters) {
-		o.name = name
+// loadSliceElemsFromEnv overrides fields of existing elements from <envVar>_<index>_*
+// variables, e.g. APP_SERVERS_0_PORT. The slice is never grown from env.
+func loadSliceElemsFromEnv(field reflect.Value, envVar string, params *parameters) (bool, error) {
+	set := false
+	for i := 0; i < field.Len(); i++ {
+		elemParams := *params
+		elemParams.envPrefix = envVar + params.prefixSeparator + strconv.Itoa(i)
+
+		elemSet, err := loadStructOrPtrFromEnv(field.Index(i), &elemParams, "", "")
+		if err != nil {
+			return false, fmt.Errorf("element %d: %w", i, err)
+		}
+		set = set || elemSet
 	}
+	return set, nil
 }
 
-// WithEnvPrefix set prefix for environment variables.
-func WithEnvPrefix(prefix string) Action {
-	return func(o *parameters) {
-		o.envPrefix = strings.TrimSuffix(strings.ToUpper(prefix), "_")
+// resolveEnv returns the value of envVar. When envVar is unset, the file named by
+// envVar_FILE is read instead (Docker secrets convention) with the trailing newline trimmed.
+func resolveEnv(envVar string, params *parameters) (string, bool, error) {
+	if value, ok := params.lookupEnv(envVar); ok {
+		params.consumeEnv(envVar)
+		params.debugf("env %s: set", envVar)
+		return params.trimEnvValue(value), true, nil
+	}
+
+	fileVar := envVar + "_FILE"
+	path, ok := params.lookupEnv(fileVar)
+	if !ok {
+		params.debugf("env %s: not set", envVar)
+		return "", false, nil
+	}
+	params.consumeEnv(fileVar)
+	params.debugf("env %s: read from file %s", envVar, path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("unread file %s from %s: %w", path, fileVar, err)
+	}
+
+	if err := checkFileMode(path, params); err != nil {
+		return "", false, fmt.Errorf("%s: %w", fileVar, err)
 	}
+
+	value := strings.TrimSuffix(string(data), "\n")
+	value = strings.TrimSuffix(value, "\r")
+	return params.trimEnvValue(value), true, nil
 }
 
-// MustLoad downloads the configuration or panics.
-func MustLoad(cfg any, paramsAction ...Action) {
-	if err := Load(cfg, paramsAction...); err != nil {
-		panic("cfg: failed to load config: " + err.Error())
+// resolveEnvAlias returns the value of the first set variable listed in the envAlias
+// tag of field and reports its use to WithDeprecationLog.
+func resolveEnvAlias(field reflect.StructField, envVar string, params *parameters) (string, bool, error) {
+	for _, alias := range envAliasNames(field, params) {
+		value, ok, err := resolveEnv(alias, params)
+		if err != nil {
+			return "", false, err
+		}
+		if !ok {
+			continue
+		}
+
+		params.debugf("env %s: deprecated, use %s", alias, envVar)
+		if params.deprecationLog != nil {
+			params.deprecationLog(alias, envVar)
+		}
+		return value, true, nil
 	}
+	return "", false, nil
 }
 
-// Load downloads the configuration
-func Load(cfg any, paramsActions ...Action) error {
-	if err := validateConfig(cfg); err != nil {
-		return fmt.Errorf("invalid config: %w", err)
+// envAliasNames returns the full names of the comma separated envAlias tag of field,
+// with the prefix applied like for the env tag.
+func envAliasNames(field reflect.StructField, params *parameters) []string {
+	tag := field.Tag.Get("envAlias")
+	if tag == "" {
+		return nil
 	}
 
-	p := defaultParameters()
-	for _, paramAction := range paramsActions {
-		paramAction(p)
+	_, opts, _ := strings.Cut(field.Tag.Get(params.envTag), ",")
+	envPrefix := params.envPrefix
+	if hasTagOption(opts, "noprefix") {
+		envPrefix = ""
 	}
 
-	// first load from YAML
-	if err := loadFromYaml(cfg, p); err != nil {
-		return fmt.Errorf("unload config file: %w", err)
+	var names []string
+	for _, alias := range strings.Split(tag, ",") {
+		alias = strings.TrimSpace(alias)
+		if alias == "" {
+			continue
+		}
+		if !params.envTagVerbatim {
+			alias = strings.ToUpper(alias)
+		}
+		names = append(names, prefixEnvName(alias, envPrefix, params.prefixSeparator))
 	}
+	return names
+}
 
-	// then override with environment variables
-	if err := loadFromEnv(cfg, p); err != nil {
-		return fmt.Errorf("load env: %w", err)
+// debugf reports an env resolution decision to WithDebugLog. Values are never
+// logged, they may be secrets.
+func (p *parameters) debugf(format string, args ...any) {
+	if p.debugLog != nil {
+		p.debugLog(fmt.Sprintf(format, args...))
 	}
+}
 
-	return nil
+// consumeEnv records that an env variable set a field, for WithDebugLog.
+func (p *parameters) consumeEnv(key string) {
+	if p.consumedEnv != nil {
+		p.consumedEnv[envKey(key, p)] = true
+	}
 }
 
-func validateConfig(cfg any) error {
-	if cfg == nil {
-		return fmt.Errorf("config must not be nil")
+// envKey normalizes key for comparison, WithCaseInsensitiveEnv ignores case.
+func envKey(key string, params *parameters) string {
+	if params.caseInsensitiveEnv {
+		return strings.ToUpper(key)
 	}
+	return key
+}
 
-	v := reflect.ValueOf(cfg)
-	if v.Kind() != reflect.Ptr {
-		return fmt.Errorf("config must be a pointer to struct")
+// skipEmptyEnv reports whether a set but empty env variable is ignored for field,
+// with WithIgnoreEmptyEnv or the skipempty tag option.
+func skipEmptyEnv(field reflect.StructField, params *parameters) bool {
+	_, opts, _ := strings.Cut(field.Tag.Get(params.envTag), ",")
+	return params.ignoreEmptyEnv || hasTagOption(opts, "skipempty")
+}
+
+// trimEnvValue trims surrounding whitespace from env values with WithTrimSpace.
+func (p *parameters) trimEnvValue(value string) string {
+	if p.trimSpace {
+		return strings.TrimSpace(value)
 	}
+	return value
+}
 
-	if v.IsNil() {
-		return fmt.Errorf("config pointer must not be nil")
+// loadStructPtrFromEnv recurses into a pointer to struct. A nil pointer is
+// allocated only when at least one of its fields is set from the environment,
+// and never for a type already walked on the current path (recursive types).
+func loadStructPtrFromEnv(field reflect.Value, params *parameters, path, envPath string) (bool, error) {
+	if !field.IsNil() {
+		return loadStructOrPtrFromEnv(field.Elem(), params, path, envPath)
 	}
 
-	if v.Elem().Kind() != reflect.Struct {
-		return fmt.Errorf("config must be a pointer to struct")
+	if params.onWalkPath(field.Type()) {
+		return false, nil
 	}
 
-	return nil
+	ptr := reflect.New(field.Type().Elem())
+	set, err := loadStructOrPtrFromEnv(ptr.Elem(), params, path, envPath)
+	if err != nil {
+		return false, err
+	}
+
+	if set {
+		field.Set(ptr)
+	}
+
+	return set, nil
 }
 
-func defaultParameters() *parameters {
-	return &parameters{
-		paths:     []string{".", "./config"},
-		name:      "config",
-		envPrefix: "APP",
+// enterWalk marks the struct type t as walked until the returned function is called.
+func (p *parameters) enterWalk(t reflect.Type) func() {
+	if p.walking == nil {
+		p.walking = make(map[reflect.Type]int)
+	}
+	p.walking[t]++
+	return func() {
+		p.walking[t]--
 	}
 }
 
-func loadFromYaml(cfg any, parameters *parameters) error {
-	for _, path := range parameters.paths {
-		fullName := filepath.Join(path, parameters.name+".yaml")
-		data, err := os.ReadFile(fullName)
-		if err != nil {
-			if os.IsNotExist(err) {
-				continue
-			}
-			return fmt.Errorf("unread file %s: %w", fullName, err)
+// onWalkPath reports whether the struct type ptr points to, through any number of
+// pointers, is already walked on the current path, e.g. Fallback *Node inside Node.
+func (p *parameters) onWalkPath(ptr reflect.Type) bool {
+	for ptr.Kind() == reflect.Ptr {
+		ptr = ptr.Elem()
+	}
+	return p.walking[ptr] > 0
+}
+
+// loadStructOrPtrFromEnv resolves v through any number of pointers (**T, []*T elements)
+// to the struct it points to.
+func loadStructOrPtrFromEnv(v reflect.Value, params *parameters, path, envPath string) (bool, error) {
+	if v.Kind() == reflect.Ptr {
+		return loadStructPtrFromEnv(v, params, path, envPath)
+	}
+	return loadStructFromEnv(v, params, path, envPath)
+}
+
+// loadMapFromEnv fills a map with string keys from every <envVar>_<KEY> variable.
+// Keys are the lowercased suffix after the name, entries are merged into the map
+// decoded from the config file. Empty values are skipped like for scalar fields,
+// see skipEmptyEnv.
+func loadMapFromEnv(field reflect.Value, structField reflect.StructField, envVar string, params *parameters) (bool, error) {
+	prefix := envVar + params.prefixSeparator
+	set := false
+	for key, value := range params.environ() {
+		if params.caseInsensitiveEnv {
+			key = strings.ToUpper(key)
+		}
+		if !strings.HasPrefix(key, prefix) || len(key) == len(prefix) {
+			continue
+		}
+		// Ключи не-строковых map из env не задать; ошибка только если переменная есть
+		if field.Type().Key().Kind() != reflect.String {
+			return false, fmt.Errorf("key %s: unsupported map key type: %s", key, field.Type().Key().Kind())
 		}
 
-		if err := yaml.Unmarshal(data, cfg); err != nil {
-			return fmt.Errorf("unparse yaml %s: %w", fullName, err)
+		params.consumeEnv(key)
+		value = params.trimEnvValue(value)
+		if value == "" && skipEmptyEnv(structField, params) {
+			params.debugf("env %s: empty, skipped", key)
+			continue
 		}
+		params.debugf("env %s: set map key %s", key, strings.ToLower(key[len(prefix):]))
 
-		return nil
+		elem := reflect.New(field.Type().Elem()).Elem()
+		if err := convertValue(elem, value, params); err != nil {
+			return false, fmt.Errorf("key %s: %w", key, err)
+		}
+
+		if field.IsNil() {
+			field.Set(reflect.MakeMap(field.Type()))
+		}
+
+		mapKey := reflect.ValueOf(strings.ToLower(key[len(prefix):])).Convert(field.Type().Key())
+		field.SetMapIndex(mapKey, elem)
+		set = true
 	}
 
-	return nil
+	return set, nil
 }
 
-func loadFromEnv(cfg any, params *parameters) error {
-	v := reflect.ValueOf(cfg).Elem()
-	return loadStructFromEnv(v, params.envPrefix)
+func getEnvVarName(field reflect.StructField, params *parameters, path string) string {
+	envTag, opts, _ := strings.Cut(field.Tag.Get(params.envTag), ",")
+	if envTag == "-" {
+		return ""
+	}
+
+	// env:"NAME,noprefix" читает переменную без глобального префикса
+	envPrefix := params.envPrefix
+	if hasTagOption(opts, "noprefix") {
+		envPrefix = ""
+	}
+
+	// Используем тег env, если указан
+	if envTag != "" {
+		if !params.envTagVerbatim {
+			envTag = strings.ToUpper(envTag)
+		}
+		return prefixEnvName(envTag, envPrefix, params.prefixSeparator)
+	}
+
+	// В режиме WithAutoEnv имя строится из пути поля: Server.Port -> SERVER_PORT
+	if params.autoEnv {
+		segments := strings.Split(path, ".")
+		for i, segment := range segments {
+			segments[i] = toEnvSegment(segment)
+		}
+		return prefixEnvName(strings.Join(segments, params.prefixSeparator), envPrefix, params.prefixSeparator)
+	}
+
+	// Если тег env не указан, НЕ создаем автоматическое имя
+	// Пользователь должен явно указать тег env для полей, которые хочет переопределять
+	return ""
 }
 
-func loadStructFromEnv(v reflect.Value, envPrefix string) error {
-	t := v.Type()
+// envPathSegment returns the auto-env path segment of field: the Go field name, or
+// with WithYamlEnvNames the name from the yaml tag without options like omitempty.
+func envPathSegment(field reflect.StructField, params *parameters) string {
+	if params.yamlEnvNames {
+		if name, _, _ := strings.Cut(field.Tag.Get(params.yamlTag), ","); name != "" && name != "-" {
+			return envKeyReplacer.Replace(name)
+		}
+	}
+	return field.Name
+}
 
-	for i := 0; i < v.NumField(); i++ {
-		field := v.Field(i)
-		if !field.CanSet() {
-			continue
+// hasTagOption reports whether the comma separated tag options contain option.
+func hasTagOption(opts, option string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if strings.TrimSpace(opt) == option {
+			return true
 		}
+	}
+	return false
+}
 
-		structField := t.Field(i)
+func prefixEnvName(envName, envPrefix, separator string) string {
+	if envPrefix != "" {
+		return envPrefix + separator + envName
+	}
+	return envName
+}
 
-		// Рекурсивно обрабатываем вложенные структуры
-		if field.Kind() == reflect.Struct {
-			if err := loadStructFromEnv(field, envPrefix); err != nil {
-				return err
+// toEnvSegment converts a Go field name to upper snake case: MaxConns -> MAX_CONNS, DBHost -> DB_HOST.
+func toEnvSegment(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				b.WriteByte('_')
 			}
-			continue
 		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// setStructField sets the field honoring its format tag: format:"bytes" parses
+// sizes like "10MB" into integer fields, format:"hex" decodes []byte fields,
+// on time.Time fields the tag is the layout.
+func setStructField(field reflect.Value, structField reflect.StructField, value string, params *parameters) error {
+	if field.Type() == timeType {
+		layout := structField.Tag.Get("format")
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		return setTimeField(field, value, layout)
+	}
+
+	if field.Type() == bytesType && structField.Tag.Get("format") == "hex" {
+		data, err := hex.DecodeString(value)
+		if err != nil {
+			return fmt.Errorf("invalid hex value: %w", err)
+		}
+		field.SetBytes(data)
+		return nil
+	}
+
+	if structField.Tag.Get("format") == "bytes" {
+		size, err := parseByteSize(value)
+		if err != nil {
+			return err
+		}
+		return convertValue(field, strconv.FormatInt(size, 10), params)
+	}
+
+	return convertValue(field, value, params)
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+var timeType = reflect.TypeOf(time.Time{})
+
+var bytesType = reflect.TypeOf([]byte(nil))
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// isTextUnmarshaler reports whether *t implements encoding.TextUnmarshaler.
+func isTextUnmarshaler(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+var urlType = reflect.TypeOf(url.URL{})
+
+// isValueType reports whether the struct type t is set from a single value instead
+// of field by field: text unmarshalers such as time.Time, url.URL and types with
+// a registered converter.
+func isValueType(t reflect.Type) bool {
+	if _, ok := lookupConverter(t); ok {
+		return true
+	}
+	return t == urlType || isTextUnmarshaler(t)
+}
+
+// isNestedStruct reports whether field is a struct that is walked field by field.
+func isNestedStruct(field reflect.Value) bool {
+	return field.Kind() == reflect.Struct && !isValueType(field.Type())
+}
+
+// isStructPtr reports whether field is a pointer to a struct that is walked field by
+// field, pointers to value types such as *time.Time are set as values.
+func isStructPtr(field reflect.Value) bool {
+	return field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.Struct &&
+		!isValueType(field.Type().Elem())
+}
+
+// isStructPtrChain reports whether t is a pointer, possibly to further pointers (**T),
+// to a struct that is walked field by field.
+func isStructPtrChain(t reflect.Type) bool {
+	if t.Kind() != reflect.Ptr {
+		return false
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !isValueType(t)
+}
 
-		envVar := getEnvVarName(structField, envPrefix)
-		if envValue, exists := os.LookupEnv(envVar); exists {
-			if err := setFieldFromEnv(field, envValue); err != nil {
-				return fmt.Errorf("set field %s from env %s: %w",
-					structField.Name, envVar, err)
+// convertValue sets field from its string form. It is the single conversion shared by
+// env variables, map values, slice and array elements, defaults, flags and overrides,
+// so every type (durations, text unmarshalers, converters, ...) works in all of them.
+// Pointers and collections are handled here, single values by convertString.
+func convertValue(field reflect.Value, value string, params *parameters) error {
+	if _, ok := lookupConverter(field.Type()); !ok {
+		switch {
+		// pointer scalars are allocated only when a value is present, so nil keeps meaning "unset"
+		case field.Kind() == reflect.Ptr:
+			ptr := reflect.New(field.Type().Elem())
+			if err := convertValue(ptr.Elem(), value, params); err != nil {
+				return err
 			}
+			field.Set(ptr)
+			return nil
+		// []byte and text unmarshalers such as net.IP are single values, not lists
+		case field.Kind() == reflect.Slice && field.Type() != bytesType && !isTextUnmarshaler(field.Type()):
+			return setSliceFromEnv(field, value, params)
+		case field.Kind() == reflect.Array && !isTextUnmarshaler(field.Type()):
+			return setArrayFromEnv(field, value, params)
 		}
 	}
 
+	converted, err := convertString(field.Type(), value)
+	if err != nil {
+		return err
+	}
+	field.Set(converted)
 	return nil
 }
 
-func getEnvVarName(field reflect.StructField, envPrefix string) string {
-	// Используем тег env, если указан
-	if envTag := field.Tag.Get("env"); envTag != "" {
-		envName := strings.ToUpper(envTag)
-		if envPrefix != "" {
-			return envPrefix + "_" + envName
+// convertString parses s into a value of type t: registered converters first, then
+// time.Time, url.URL, text unmarshalers, base64 []byte, time.Duration and the basic kinds.
+func convertString(t reflect.Type, s string) (reflect.Value, error) {
+	if convert, ok := lookupConverter(t); ok {
+		return convertWithConverter(t, s, convert)
+	}
+
+	// time.Time implements encoding.TextUnmarshaler, checked first for a clearer error
+	if t == timeType {
+		return convertTime(s, time.RFC3339)
+	}
+
+	if t == urlType {
+		u, err := url.Parse(s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid URL %q: %w", s, errors.Unwrap(err))
 		}
-		return envName
+		return reflect.ValueOf(*u), nil
 	}
 
-	// Если тег env не указан, НЕ создаем автоматическое имя
-	// Пользователь должен явно указать тег env для полей, которые хочет переопределять
-	return ""
-}
+	if isTextUnmarshaler(t) {
+		ptr := reflect.New(t)
+		if err := ptr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return reflect.Value{}, err
+		}
+		return ptr.Elem(), nil
+	}
+
+	// []byte holds binary data passed as base64, not a comma separated list of numbers
+	if t == bytesType {
+		data, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid base64 value: %w", err)
+		}
+		return reflect.ValueOf(data), nil
+	}
+
+	// time.Duration is an int64 kind, so it must be checked before the generic int case
+	if t == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid duration %q", s)
+		}
+		return reflect.ValueOf(d), nil
+	}
 
-func setFieldFromEnv(field reflect.Value, value string) error {
-	switch field.Kind() {
+	v := reflect.New(t).Elem()
+	switch t.Kind() {
 	case reflect.String:
-		field.SetString(value)
+		v.SetString(s)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		intVal, err := strconv.ParseInt(value, 10, 64)
+		intVal, err := strconv.ParseInt(s, 10, t.Bits())
 		if err != nil {
-			return err
+			return reflect.Value{}, invalidValueError(t, s, err)
 		}
-		field.SetInt(intVal)
+		v.SetInt(intVal)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		uintVal, err := strconv.ParseUint(value, 10, 64)
+		uintVal, err := strconv.ParseUint(s, 10, t.Bits())
 		if err != nil {
-			return err
+			return reflect.Value{}, invalidValueError(t, s, err)
 		}
-		field.SetUint(uintVal)
+		v.SetUint(uintVal)
 	case reflect.Float32, reflect.Float64:
-		floatVal, err := strconv.ParseFloat(value, 64)
+		floatVal, err := strconv.ParseFloat(s, t.Bits())
 		if err != nil {
-			return err
+			return reflect.Value{}, invalidValueError(t, s, err)
 		}
-		field.SetFloat(floatVal)
+		v.SetFloat(floatVal)
 	case reflect.Bool:
-		boolVal, err := strconv.ParseBool(value)
+		boolVal, err := parseBool(s)
 		if err != nil {
-			return err
+			return reflect.Value{}, invalidValueError(t, s, err)
 		}
-		field.SetBool(boolVal)
+		v.SetBool(boolVal)
 	default:
-		return fmt.Errorf("unsupported type: %s", field.Kind())
+		return reflect.Value{}, fmt.Errorf("unsupported type: %s", t.Kind())
+	}
+	return v, nil
+}
+
+func setTimeField(field reflect.Value, value, layout string) error {
+	t, err := convertTime(value, layout)
+	if err != nil {
+		return err
+	}
+	field.Set(t)
+	return nil
+}
+
+func convertTime(value, layout string) (reflect.Value, error) {
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("invalid time %q, expected layout %q", value, layout)
+	}
+	return reflect.ValueOf(t), nil
+}
+
+// parseBool extends strconv.ParseBool with yes/no, on/off, enabled/disabled and y/n,
+// case-insensitive.
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "yes", "y", "on", "enabled":
+		return true, nil
+	case "no", "n", "off", "disabled":
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// invalidValueError reports a value that cannot be parsed into type t.
+func invalidValueError(t reflect.Type, value string, err error) error {
+	if errors.Is(err, strconv.ErrRange) {
+		return fmt.Errorf("value %q is out of range for %s", value, t.Kind())
+	}
+	return fmt.Errorf("value %q is not a valid %s", value, t.Kind())
+}
+
+func setSliceFromEnv(field reflect.Value, value string, params *parameters) error {
+	if value == "" {
+		field.Set(reflect.MakeSlice(field.Type(), 0, 0))
+		return nil
+	}
+
+	parts := strings.Split(value, params.sliceSeparator)
+	slice := reflect.MakeSlice(field.Type(), len(parts), len(parts))
+	for i, part := range parts {
+		if err := convertValue(slice.Index(i), part, params); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+
+	field.Set(slice)
+	return nil
+}
+
+func setArrayFromEnv(field reflect.Value, value string, params *parameters) error {
+	var parts []string
+	if value != "" {
+		parts = strings.Split(value, params.sliceSeparator)
+	}
+
+	if len(parts) != field.Len() {
+		return fmt.Errorf("expected %d elements, got %d", field.Len(), len(parts))
 	}
+
+	array := reflect.New(field.Type()).Elem()
+	for i, part := range parts {
+		if err := convertValue(array.Index(i), part, params); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+
+	field.Set(array)
 	return nil
 }
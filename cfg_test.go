@@ -1,8 +1,18 @@
 package cfg
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 )
 
 type TestConfig struct {
@@ -326,3 +336,2936 @@ func TestNoEnvOverrideWithoutTag(t *testing.T) {
 		t.Errorf("Expected version '2.0.0' from env, got '%s'", cfg.Version)
 	}
 }
+
+func TestDurationFromEnv(t *testing.T) {
+	type DurationConfig struct {
+		Timeout time.Duration `yaml:"timeout" env:"TIMEOUT"`
+	}
+
+	err := os.Setenv("TEST_TIMEOUT", "30s")
+	if err != nil {
+		t.Fatalf("Failed to set env TEST_TIMEOUT: %v", err)
+	}
+	defer func() {
+		_ = os.Unsetenv("TEST_TIMEOUT")
+	}()
+
+	var cfg DurationConfig
+
+	err = Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Timeout != 30*time.Second {
+		t.Errorf("Expected timeout 30s from env, got %s", cfg.Timeout)
+	}
+}
+
+func TestInvalidDurationFromEnv(t *testing.T) {
+	type DurationConfig struct {
+		Timeout time.Duration `yaml:"timeout" env:"TIMEOUT"`
+	}
+
+	err := os.Setenv("TEST_TIMEOUT", "xyz")
+	if err != nil {
+		t.Fatalf("Failed to set env TEST_TIMEOUT: %v", err)
+	}
+	defer func() {
+		_ = os.Unsetenv("TEST_TIMEOUT")
+	}()
+
+	var cfg DurationConfig
+
+	err = Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+	)
+
+	if err == nil {
+		t.Fatal("Expected error for invalid duration")
+	}
+
+	if !strings.Contains(err.Error(), `invalid duration "xyz"`) {
+		t.Errorf("Expected invalid duration error, got: %v", err)
+	}
+}
+
+func TestDefaultTag(t *testing.T) {
+	type DefaultConfig struct {
+		Name   string `yaml:"name" default:"default-app"`
+		Server struct {
+			Port    int           `yaml:"port" env:"SERVER_PORT" default:"8080"`
+			Debug   bool          `yaml:"debug" default:"true"`
+			Timeout time.Duration `yaml:"timeout" default:"5s"`
+		} `yaml:"server"`
+	}
+
+	var cfg DefaultConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Name != "default-app" {
+		t.Errorf("Expected name 'default-app' from default, got '%s'", cfg.Name)
+	}
+
+	if cfg.Server.Port != 8080 {
+		t.Errorf("Expected server.port 8080 from default, got %d", cfg.Server.Port)
+	}
+
+	if cfg.Server.Timeout != 5*time.Second {
+		t.Errorf("Expected server.timeout 5s from default, got %s", cfg.Server.Timeout)
+	}
+}
+
+func TestDefaultTagOverriddenByYamlAndEnv(t *testing.T) {
+	type DefaultConfig struct {
+		Server struct {
+			Host  string `yaml:"host" default:"0.0.0.0"`
+			Port  int    `yaml:"port" env:"SERVER_PORT" default:"1"`
+			Debug bool   `yaml:"debug" default:"true"`
+		} `yaml:"server"`
+	}
+
+	err := os.Setenv("TEST_SERVER_PORT", "9090")
+	if err != nil {
+		t.Fatalf("Failed to set env TEST_SERVER_PORT: %v", err)
+	}
+	defer func() {
+		_ = os.Unsetenv("TEST_SERVER_PORT")
+	}()
+
+	var cfg DefaultConfig
+
+	err = Load(&cfg,
+		WithPaths("./test"),
+		WithName("config_override"),
+		WithEnvPrefix("TEST"),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// config_override.yaml не содержит host - остается значение по умолчанию
+	if cfg.Server.Host != "0.0.0.0" {
+		t.Errorf("Expected server.host '0.0.0.0' from default, got '%s'", cfg.Server.Host)
+	}
+
+	if cfg.Server.Port != 9090 {
+		t.Errorf("Expected server.port 9090 from env, got %d", cfg.Server.Port)
+	}
+
+	// Явный false в YAML не должен затираться значением по умолчанию
+	if cfg.Server.Debug != false {
+		t.Errorf("Expected server.debug false from config_override.yaml, got %t", cfg.Server.Debug)
+	}
+}
+
+func TestInvalidDefaultTag(t *testing.T) {
+	type DefaultConfig struct {
+		Port int `yaml:"port" default:"abc"`
+	}
+
+	var cfg DefaultConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+	)
+
+	if err == nil {
+		t.Error("Expected error for invalid default value")
+	}
+}
+
+func TestSliceFromEnv(t *testing.T) {
+	type SliceConfig struct {
+		Hosts []string `yaml:"hosts" env:"HOSTS"`
+		Ports []int    `yaml:"ports" env:"PORTS"`
+	}
+
+	err := os.Setenv("TEST_HOSTS", "a,b,c")
+	if err != nil {
+		t.Fatalf("Failed to set env TEST_HOSTS: %v", err)
+	}
+	err = os.Setenv("TEST_PORTS", "80,443")
+	if err != nil {
+		t.Fatalf("Failed to set env TEST_PORTS: %v", err)
+	}
+	defer func() {
+		_ = os.Unsetenv("TEST_HOSTS")
+		_ = os.Unsetenv("TEST_PORTS")
+	}()
+
+	var cfg SliceConfig
+
+	err = Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(cfg.Hosts, []string{"a", "b", "c"}) {
+		t.Errorf("Expected hosts [a b c] from env, got %v", cfg.Hosts)
+	}
+
+	if !reflect.DeepEqual(cfg.Ports, []int{80, 443}) {
+		t.Errorf("Expected ports [80 443] from env, got %v", cfg.Ports)
+	}
+}
+
+func TestSliceSeparatorAndEmptyValue(t *testing.T) {
+	type SliceConfig struct {
+		Hosts []string `yaml:"hosts" env:"HOSTS"`
+		Tags  []string `yaml:"tags" env:"TAGS"`
+	}
+
+	err := os.Setenv("TEST_HOSTS", "a;b")
+	if err != nil {
+		t.Fatalf("Failed to set env TEST_HOSTS: %v", err)
+	}
+	err = os.Setenv("TEST_TAGS", "")
+	if err != nil {
+		t.Fatalf("Failed to set env TEST_TAGS: %v", err)
+	}
+	defer func() {
+		_ = os.Unsetenv("TEST_HOSTS")
+		_ = os.Unsetenv("TEST_TAGS")
+	}()
+
+	var cfg SliceConfig
+
+	err = Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithSliceSeparator(";"),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(cfg.Hosts, []string{"a", "b"}) {
+		t.Errorf("Expected hosts [a b] from env, got %v", cfg.Hosts)
+	}
+
+	if cfg.Tags == nil || len(cfg.Tags) != 0 {
+		t.Errorf("Expected empty non-nil tags from empty env, got %#v", cfg.Tags)
+	}
+}
+
+func TestLoadFromJsonFile(t *testing.T) {
+	type JsonConfig struct {
+		App struct {
+			Name string `json:"name" env:"APP_NAME"`
+		} `json:"app"`
+		Server struct {
+			Host string `json:"host"`
+			Port int    `json:"port" env:"SERVER_PORT"`
+		} `json:"server"`
+	}
+
+	err := os.Setenv("TEST_SERVER_PORT", "9090")
+	if err != nil {
+		t.Fatalf("Failed to set env TEST_SERVER_PORT: %v", err)
+	}
+	defer func() {
+		_ = os.Unsetenv("TEST_SERVER_PORT")
+	}()
+
+	var cfg JsonConfig
+
+	err = Load(&cfg,
+		WithPaths("./test"),
+		WithName("json_config"),
+		WithEnvPrefix("TEST"),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.App.Name != "json-app" {
+		t.Errorf("Expected app.name 'json-app', got '%s'", cfg.App.Name)
+	}
+
+	if cfg.Server.Host != "json.localhost" {
+		t.Errorf("Expected server.host 'json.localhost', got '%s'", cfg.Server.Host)
+	}
+
+	if cfg.Server.Port != 9090 {
+		t.Errorf("Expected server.port 9090 from env, got %d", cfg.Server.Port)
+	}
+}
+
+func TestPointerStructFromEnv(t *testing.T) {
+	type DBConfig struct {
+		Host string `yaml:"host" env:"DB_HOST"`
+		Port int    `yaml:"port" env:"DB_PORT"`
+	}
+	type PointerConfig struct {
+		Database *DBConfig `yaml:"database"`
+		Cache    *DBConfig `yaml:"cache"`
+		Server   *struct {
+			Port int `yaml:"port" env:"SERVER_PORT"`
+		} `yaml:"server"`
+	}
+
+	err := os.Setenv("TEST_DB_HOST", "env.db.localhost")
+	if err != nil {
+		t.Fatalf("Failed to set env TEST_DB_HOST: %v", err)
+	}
+	defer func() {
+		_ = os.Unsetenv("TEST_DB_HOST")
+	}()
+
+	var cfg PointerConfig
+
+	err = Load(&cfg,
+		WithPaths("./test"),
+		WithName("config"),
+		WithEnvPrefix("TEST"),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Указатель заполнен из YAML, env переопределяет поле
+	if cfg.Database == nil {
+		t.Fatal("Expected database to be decoded from YAML")
+	}
+
+	if cfg.Database.Host != "env.db.localhost" {
+		t.Errorf("Expected database.host 'env.db.localhost' from env, got '%s'", cfg.Database.Host)
+	}
+
+	if cfg.Database.Port != 5432 {
+		t.Errorf("Expected database.port 5432 from YAML, got %d", cfg.Database.Port)
+	}
+
+	if cfg.Server == nil || cfg.Server.Port != 3000 {
+		t.Errorf("Expected server.port 3000 from YAML, got %+v", cfg.Server)
+	}
+
+	// Указатель отсутствует в YAML, но env задан - должен быть создан
+	if cfg.Cache == nil {
+		t.Fatal("Expected cache to be allocated from env")
+	}
+
+	if cfg.Cache.Host != "env.db.localhost" {
+		t.Errorf("Expected cache.host 'env.db.localhost' from env, got '%s'", cfg.Cache.Host)
+	}
+}
+
+func TestNilPointerStructWithoutEnv(t *testing.T) {
+	type PointerConfig struct {
+		Database *struct {
+			Host string `yaml:"host" env:"DB_HOST"`
+		} `yaml:"database"`
+	}
+
+	var cfg PointerConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Database != nil {
+		t.Errorf("Expected database to stay nil without env vars, got %+v", cfg.Database)
+	}
+}
+
+type TestCommon struct {
+	Name    string `yaml:"name" env:"NAME"`
+	Version string `yaml:"version" env:"VERSION"`
+}
+
+type testHidden struct {
+	Region string `yaml:"region" env:"REGION"`
+}
+
+func TestEmbeddedStructFromEnv(t *testing.T) {
+	type EmbeddedConfig struct {
+		TestCommon `yaml:",inline"`
+		testHidden `yaml:",inline"`
+	}
+
+	err := os.Setenv("TEST_VERSION", "2.0.0")
+	if err != nil {
+		t.Fatalf("Failed to set env TEST_VERSION: %v", err)
+	}
+	err = os.Setenv("TEST_REGION", "eu")
+	if err != nil {
+		t.Fatalf("Failed to set env TEST_REGION: %v", err)
+	}
+	defer func() {
+		_ = os.Unsetenv("TEST_VERSION")
+		_ = os.Unsetenv("TEST_REGION")
+	}()
+
+	var cfg EmbeddedConfig
+
+	err = Load(&cfg,
+		WithPaths("./test"),
+		WithName("simple_config"),
+		WithEnvPrefix("TEST"),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Name != "simple-app" {
+		t.Errorf("Expected name 'simple-app' from inlined YAML, got '%s'", cfg.Name)
+	}
+
+	// Тег env встроенной структуры разрешается без дополнительного сегмента префикса
+	if cfg.Version != "2.0.0" {
+		t.Errorf("Expected version '2.0.0' from TEST_VERSION, got '%s'", cfg.Version)
+	}
+
+	if cfg.Region != "eu" {
+		t.Errorf("Expected region 'eu' from TEST_REGION, got '%s'", cfg.Region)
+	}
+}
+
+func TestWithEnv(t *testing.T) {
+	t.Parallel()
+
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("config"),
+		WithEnvPrefix("HERMETIC"),
+		WithEnv(map[string]string{
+			"HERMETIC_SERVER_PORT": "6060",
+			"HERMETIC_DB_NAME":     "map_db",
+		}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Server.Port != 6060 {
+		t.Errorf("Expected server.port 6060 from env map, got %d", cfg.Server.Port)
+	}
+
+	if cfg.Database.Name != "map_db" {
+		t.Errorf("Expected database.name 'map_db' from env map, got '%s'", cfg.Database.Name)
+	}
+}
+
+func TestWithEnvIgnoresProcessEnv(t *testing.T) {
+	err := os.Setenv("TEST_SERVER_PORT", "9090")
+	if err != nil {
+		t.Fatalf("Failed to set env TEST_SERVER_PORT: %v", err)
+	}
+	defer func() {
+		_ = os.Unsetenv("TEST_SERVER_PORT")
+	}()
+
+	var cfg TestConfig
+
+	err = Load(&cfg,
+		WithPaths("./test"),
+		WithName("config"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Server.Port != 3000 {
+		t.Errorf("Expected server.port 3000 from YAML, got %d", cfg.Server.Port)
+	}
+}
+
+func TestStrictUnknownKey(t *testing.T) {
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("typo_config"),
+		WithStrict(),
+	)
+
+	if err == nil {
+		t.Fatal("Expected error for unknown key in strict mode")
+	}
+
+	if !strings.Contains(err.Error(), "typo_config.yaml") {
+		t.Errorf("Expected error to mention file name, got: %v", err)
+	}
+}
+
+func TestNonStrictUnknownKey(t *testing.T) {
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("typo_config"),
+	)
+
+	if err != nil {
+		t.Fatalf("Load should ignore unknown keys by default, got: %v", err)
+	}
+
+	if cfg.Server.Host != "localhost" {
+		t.Errorf("Expected server.host 'localhost', got '%s'", cfg.Server.Host)
+	}
+}
+
+func TestExpandEnvInYaml(t *testing.T) {
+	t.Parallel()
+
+	type ExpandConfig struct {
+		URL     string   `yaml:"url"`
+		Price   string   `yaml:"price"`
+		Missing string   `yaml:"missing"`
+		Port    int      `yaml:"port"`
+		Hosts   []string `yaml:"hosts"`
+	}
+
+	var cfg ExpandConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("expand_config"),
+		WithEnvPrefix("TEST"),
+		WithExpandEnv(),
+		WithEnv(map[string]string{
+			"TEST_DB_HOST": "db.localhost",
+			"DB_PORT":      "6432",
+		}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// DB_HOST берется с префиксом, DB_PORT - без префикса
+	if cfg.URL != "postgres://db.localhost:6432/db" {
+		t.Errorf("Expected expanded url, got '%s'", cfg.URL)
+	}
+
+	if cfg.Price != "$100" {
+		t.Errorf("Expected $$ to become literal $, got '%s'", cfg.Price)
+	}
+
+	if cfg.Missing != "[]" {
+		t.Errorf("Expected unknown variable to expand to empty string, got '%s'", cfg.Missing)
+	}
+
+	if cfg.Port != 5432 {
+		t.Errorf("Expected port 5432, got %d", cfg.Port)
+	}
+
+	if len(cfg.Hosts) != 1 || cfg.Hosts[0] != "db.localhost" {
+		t.Errorf("Expected expanded hosts [db.localhost], got %v", cfg.Hosts)
+	}
+}
+
+func TestExpandEnvInMapsAndInterfaces(t *testing.T) {
+	t.Parallel()
+
+	type Labels map[string]string
+	type ExpandConfig struct {
+		Labels  Labels            `yaml:"labels"`
+		Headers map[string]string `yaml:"headers"`
+		Extra   map[string]any    `yaml:"extra"`
+		Value   any               `yaml:"value"`
+	}
+
+	yamlData := `labels:
+  host: ${DB_HOST}
+headers:
+  auth: "Bearer ${TOKEN}"
+extra:
+  url: http://${DB_HOST}
+  nested:
+    list: ["${TOKEN}", 1]
+  port: 80
+value: ${TOKEN}
+`
+
+	var cfg ExpandConfig
+	err := LoadReader(&cfg, strings.NewReader(yamlData),
+		WithExpandEnv(),
+		WithEnv(map[string]string{
+			"DB_HOST": "db.localhost",
+			"TOKEN":   "secret",
+		}),
+	)
+	if err != nil {
+		t.Fatalf("LoadReader failed: %v", err)
+	}
+
+	if cfg.Labels["host"] != "db.localhost" {
+		t.Errorf("Expected expanded labels.host, got '%s'", cfg.Labels["host"])
+	}
+
+	if cfg.Headers["auth"] != "Bearer secret" {
+		t.Errorf("Expected expanded headers.auth, got '%s'", cfg.Headers["auth"])
+	}
+
+	if cfg.Extra["url"] != "http://db.localhost" || cfg.Extra["port"] != 80 {
+		t.Errorf("Expected expanded extra.url and untouched extra.port, got %v", cfg.Extra)
+	}
+
+	// Вложенные map и списки внутри any тоже раскрываются
+	list := cfg.Extra["nested"].(map[string]any)["list"].([]any)
+	if list[0] != "secret" || list[1] != 1 {
+		t.Errorf("Expected expanded extra.nested.list, got %v", list)
+	}
+
+	if cfg.Value != "secret" {
+		t.Errorf("Expected expanded value, got %v", cfg.Value)
+	}
+}
+
+func TestNoExpandEnvByDefault(t *testing.T) {
+	t.Parallel()
+
+	type ExpandConfig struct {
+		URL string `yaml:"url"`
+	}
+
+	var cfg ExpandConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("expand_config"),
+		WithEnv(map[string]string{"DB_HOST": "db.localhost"}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.URL != "postgres://${DB_HOST}:${DB_PORT}/db" {
+		t.Errorf("Expected url to stay unexpanded, got '%s'", cfg.URL)
+	}
+}
+
+func TestMergeConfigFiles(t *testing.T) {
+	t.Parallel()
+
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithPaths("./test", "./test/override"),
+		WithName("config"),
+		WithMerge(),
+		WithEnv(map[string]string{}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Из базового файла
+	if cfg.App.Name != "test-app" {
+		t.Errorf("Expected app.name 'test-app' from base file, got '%s'", cfg.App.Name)
+	}
+
+	if cfg.Database.Host != "db.localhost" {
+		t.Errorf("Expected database.host 'db.localhost' from base file, got '%s'", cfg.Database.Host)
+	}
+
+	// Из файла переопределения
+	if cfg.Server.Port != 8080 {
+		t.Errorf("Expected server.port 8080 from override file, got %d", cfg.Server.Port)
+	}
+
+	if cfg.Database.Name != "override_db" {
+		t.Errorf("Expected database.name 'override_db' from override file, got '%s'", cfg.Database.Name)
+	}
+}
+
+func TestFirstFileWinsWithoutMerge(t *testing.T) {
+	t.Parallel()
+
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithPaths("./test/override", "./test"),
+		WithName("config"),
+		WithEnv(map[string]string{}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Server.Port != 8080 {
+		t.Errorf("Expected server.port 8080 from first file, got %d", cfg.Server.Port)
+	}
+
+	if cfg.App.Name != "" {
+		t.Errorf("Expected app.name to be empty without merge, got '%s'", cfg.App.Name)
+	}
+}
+
+func TestLoadReader(t *testing.T) {
+	t.Parallel()
+
+	var cfg TestConfig
+
+	err := LoadReader(&cfg, strings.NewReader("server:\n  host: reader.localhost\n  port: 3000\n"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_SERVER_PORT": "9090"}),
+	)
+
+	if err != nil {
+		t.Fatalf("LoadReader failed: %v", err)
+	}
+
+	if cfg.Server.Host != "reader.localhost" {
+		t.Errorf("Expected server.host 'reader.localhost', got '%s'", cfg.Server.Host)
+	}
+
+	if cfg.Server.Port != 9090 {
+		t.Errorf("Expected server.port 9090 from env, got %d", cfg.Server.Port)
+	}
+}
+
+func TestLoadReaderWithFormat(t *testing.T) {
+	t.Parallel()
+
+	var cfg struct {
+		Name string `json:"appName"`
+	}
+
+	err := LoadReader(&cfg, strings.NewReader(`{"appName": "json-reader"}`),
+		WithFormat("json"),
+		WithEnv(map[string]string{}),
+	)
+
+	if err != nil {
+		t.Fatalf("LoadReader failed: %v", err)
+	}
+
+	if cfg.Name != "json-reader" {
+		t.Errorf("Expected name 'json-reader', got '%s'", cfg.Name)
+	}
+
+	err = LoadReader(&cfg, strings.NewReader(""), WithFormat("xml"))
+	if err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
+
+func TestLoadFromFS(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"config/config.yaml": &fstest.MapFile{
+			Data: []byte("app:\n  name: embedded-app\nserver:\n  port: 1000\n"),
+		},
+	}
+
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithPaths(".", "./config"),
+		WithFS(fsys),
+		WithEnv(map[string]string{}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.App.Name != "embedded-app" {
+		t.Errorf("Expected app.name 'embedded-app' from FS, got '%s'", cfg.App.Name)
+	}
+}
+
+func TestLoadFromFSMergedWithDisk(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"test/config.yaml": &fstest.MapFile{
+			Data: []byte("app:\n  name: embedded-app\n  version: 0.0.1\nserver:\n  port: 1000\n"),
+		},
+	}
+
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithPaths("./test/override", "./test"),
+		WithFS(fsys),
+		WithMerge(),
+		WithEnv(map[string]string{}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// test/config.yaml с диска переопределяет встроенный файл
+	if cfg.App.Name != "test-app" {
+		t.Errorf("Expected app.name 'test-app' from disk, got '%s'", cfg.App.Name)
+	}
+
+	// test/override/config.yaml применяется раньше test/config.yaml
+	if cfg.Server.Port != 3000 {
+		t.Errorf("Expected server.port 3000 from disk, got %d", cfg.Server.Port)
+	}
+
+	if cfg.Database.Name != "test_db" {
+		t.Errorf("Expected database.name 'test_db' from disk, got '%s'", cfg.Database.Name)
+	}
+}
+
+func TestLoadInto(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := LoadInto[TestConfig](
+		WithPaths("./test"),
+		WithName("config"),
+		WithEnv(map[string]string{}),
+	)
+
+	if err != nil {
+		t.Fatalf("LoadInto failed: %v", err)
+	}
+
+	if cfg.App.Name != "test-app" {
+		t.Errorf("Expected app.name 'test-app', got '%s'", cfg.App.Name)
+	}
+}
+
+func TestMustLoadIntoPanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected MustLoadInto to panic on non-struct type")
+		}
+	}()
+
+	MustLoadInto[int](WithName("missing"))
+}
+
+func TestNonPointerConfigMessage(t *testing.T) {
+	var cfg TestConfig
+
+	err := Load(cfg) // Должен быть &cfg
+
+	if err == nil {
+		t.Fatal("Expected error for non-pointer config")
+	}
+
+	if !strings.Contains(err.Error(), "did you mean to pass a pointer (&cfg)?") {
+		t.Errorf("Expected error to suggest passing a pointer, got: %v", err)
+	}
+
+	defer func() {
+		r := recover()
+		if r == nil || !strings.Contains(r.(string), "(&cfg)") {
+			t.Errorf("Expected MustLoad panic to suggest passing a pointer, got: %v", r)
+		}
+	}()
+
+	MustLoad(cfg)
+}
+
+func TestMapFromEnv(t *testing.T) {
+	t.Parallel()
+
+	type MapConfig struct {
+		Labels  map[string]string `yaml:"labels" env:"LABEL"`
+		Limits  map[string]int    `yaml:"limits" env:"LIMIT"`
+		Missing map[string]string `yaml:"missing" env:"MISSING"`
+	}
+
+	var cfg MapConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("labels_config"),
+		WithEnvPrefix("APP"),
+		WithEnv(map[string]string{
+			"APP_LABEL_TEAM":      "core",
+			"APP_LABEL_OWNER_ID":  "42",
+			"APP_LIMIT_REQUESTS":  "100",
+			"APP_LABELS_UNRELATE": "x",
+		}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// env переопределяет ключ из YAML, остальные ключи YAML сохраняются
+	expected := map[string]string{"team": "core", "tier": "backend", "owner_id": "42"}
+	if !reflect.DeepEqual(cfg.Labels, expected) {
+		t.Errorf("Expected labels %v, got %v", expected, cfg.Labels)
+	}
+
+	if cfg.Limits["requests"] != 100 {
+		t.Errorf("Expected limits[requests] 100 from env, got %v", cfg.Limits)
+	}
+
+	if cfg.Missing != nil {
+		t.Errorf("Expected missing map to stay nil, got %v", cfg.Missing)
+	}
+}
+
+type testLevel struct {
+	Value int
+}
+
+func (l *testLevel) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "low":
+		l.Value = 1
+	case "high":
+		l.Value = 2
+	default:
+		return fmt.Errorf("unknown level %q", text)
+	}
+	return nil
+}
+
+func TestTextUnmarshalerFromEnv(t *testing.T) {
+	t.Parallel()
+
+	type TextConfig struct {
+		IP    net.IP    `yaml:"ip" env:"IP"`
+		Level testLevel `yaml:"level" env:"LEVEL"`
+	}
+
+	var cfg TextConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{
+			"TEST_IP":    "10.0.0.1",
+			"TEST_LEVEL": "high",
+		}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if !cfg.IP.Equal(net.ParseIP("10.0.0.1")) {
+		t.Errorf("Expected ip 10.0.0.1 from env, got %s", cfg.IP)
+	}
+
+	if cfg.Level.Value != 2 {
+		t.Errorf("Expected level 2 from env, got %d", cfg.Level.Value)
+	}
+
+	err = Load(&cfg,
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_LEVEL": "extreme"}),
+	)
+
+	if err == nil {
+		t.Error("Expected error for invalid text value")
+	}
+}
+
+func TestProfileConfig(t *testing.T) {
+	t.Parallel()
+
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("config"),
+		WithProfile("prod"),
+		WithMerge(),
+		WithEnv(map[string]string{}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Server.Host != "prod.example.com" {
+		t.Errorf("Expected server.host 'prod.example.com' from profile file, got '%s'", cfg.Server.Host)
+	}
+
+	if cfg.Server.Debug != false {
+		t.Errorf("Expected server.debug false from profile file, got %t", cfg.Server.Debug)
+	}
+
+	if cfg.Server.Port != 3000 {
+		t.Errorf("Expected server.port 3000 from base file, got %d", cfg.Server.Port)
+	}
+}
+
+func TestMissingProfileConfig(t *testing.T) {
+	t.Parallel()
+
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("config"),
+		WithProfile("staging"),
+		WithMerge(),
+		WithEnv(map[string]string{}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load should not fail when profile file not found, got: %v", err)
+	}
+
+	if cfg.Server.Host != "localhost" {
+		t.Errorf("Expected server.host 'localhost' from base file, got '%s'", cfg.Server.Host)
+	}
+}
+
+func TestInvalidEnvValueMessage(t *testing.T) {
+	t.Parallel()
+
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("config"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_SERVER_PORT": "abc"}),
+	)
+
+	if err == nil {
+		t.Fatal("Expected error for invalid int value")
+	}
+
+	expected := `field Server.Port: env TEST_SERVER_PORT: value "abc" is not a valid int`
+	if !strings.Contains(err.Error(), expected) {
+		t.Errorf("Expected error to contain '%s', got: %v", expected, err)
+	}
+}
+
+func TestIntegerOverflowFromEnv(t *testing.T) {
+	t.Parallel()
+
+	type SizedConfig struct {
+		Small  int8    `yaml:"small" env:"SMALL"`
+		Byte   uint8   `yaml:"byte" env:"BYTE"`
+		Ratio  float32 `yaml:"ratio" env:"RATIO"`
+		Signed int16   `yaml:"signed" env:"SIGNED"`
+	}
+
+	tests := []struct {
+		name  string
+		env   string
+		value string
+	}{
+		{name: "int8 overflow", env: "TEST_SMALL", value: "128"},
+		{name: "int8 underflow", env: "TEST_SMALL", value: "-129"},
+		{name: "uint8 overflow", env: "TEST_BYTE", value: "256"},
+		{name: "float32 overflow", env: "TEST_RATIO", value: "1e39"},
+		{name: "int16 overflow", env: "TEST_SIGNED", value: "99999"},
+	}
+
+	for _, tt := range tests {
+		var cfg SizedConfig
+
+		err := Load(&cfg,
+			WithName("missing"),
+			WithEnvPrefix("TEST"),
+			WithEnv(map[string]string{tt.env: tt.value}),
+		)
+
+		if err == nil {
+			t.Errorf("%s: expected error for value %s, got field values %+v", tt.name, tt.value, cfg)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), "out of range") {
+			t.Errorf("%s: expected out of range error, got: %v", tt.name, err)
+		}
+	}
+
+	var cfg SizedConfig
+
+	err := Load(&cfg,
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{
+			"TEST_SMALL": "-128",
+			"TEST_BYTE":  "255",
+		}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed for boundary values: %v", err)
+	}
+
+	if cfg.Small != -128 || cfg.Byte != 255 {
+		t.Errorf("Expected boundary values -128 and 255, got %d and %d", cfg.Small, cfg.Byte)
+	}
+}
+
+func TestAutoEnv(t *testing.T) {
+	t.Parallel()
+
+	type AutoConfig struct {
+		TestCommon `yaml:",inline"`
+		Server     struct {
+			Host     string `yaml:"host"`
+			Port     int    `yaml:"port" env:"PORT"`
+			MaxConns int    `yaml:"max_conns"`
+		} `yaml:"server"`
+		Database *struct {
+			DBHost string `yaml:"db_host"`
+		} `yaml:"database"`
+	}
+
+	var cfg AutoConfig
+
+	err := Load(&cfg,
+		WithName("missing"),
+		WithEnvPrefix("APP"),
+		WithAutoEnv(),
+		WithEnv(map[string]string{
+			"APP_SERVER_HOST":      "auto.localhost",
+			"APP_SERVER_PORT":      "1111",
+			"APP_PORT":             "2222",
+			"APP_SERVER_MAX_CONNS": "10",
+			"APP_DATABASE_DB_HOST": "db.auto",
+			"APP_NAME":             "auto-app",
+		}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Server.Host != "auto.localhost" {
+		t.Errorf("Expected server.host 'auto.localhost' from APP_SERVER_HOST, got '%s'", cfg.Server.Host)
+	}
+
+	// Явный тег env приоритетнее автоматического имени
+	if cfg.Server.Port != 2222 {
+		t.Errorf("Expected server.port 2222 from APP_PORT, got %d", cfg.Server.Port)
+	}
+
+	if cfg.Server.MaxConns != 10 {
+		t.Errorf("Expected server.max_conns 10 from APP_SERVER_MAX_CONNS, got %d", cfg.Server.MaxConns)
+	}
+
+	if cfg.Database == nil || cfg.Database.DBHost != "db.auto" {
+		t.Errorf("Expected database.db_host 'db.auto' from APP_DATABASE_DB_HOST, got %+v", cfg.Database)
+	}
+
+	if cfg.Name != "auto-app" {
+		t.Errorf("Expected name 'auto-app' from APP_NAME, got '%s'", cfg.Name)
+	}
+}
+
+func TestAutoEnvNonStringMapKey(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Ports map[int]string `yaml:"ports"`
+	}
+
+	// Без переменных APP_PORTS_* map с int-ключами не мешает загрузке
+	var cfg Config
+	err := Load(&cfg,
+		WithName("missing"),
+		WithEnvPrefix("APP"),
+		WithAutoEnv(),
+		WithEnv(map[string]string{"APP_NAME": "auto-app"}),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	err = Load(&cfg,
+		WithName("missing"),
+		WithEnvPrefix("APP"),
+		WithAutoEnv(),
+		WithEnv(map[string]string{"APP_PORTS_80": "http"}),
+	)
+	if err == nil || !strings.Contains(err.Error(), "unsupported map key type") {
+		t.Errorf("Expected unsupported map key type error, got %v", err)
+	}
+}
+
+func TestToEnvSegment(t *testing.T) {
+	tests := map[string]string{
+		"Port":      "PORT",
+		"MaxConns":  "MAX_CONNS",
+		"DBHost":    "DB_HOST",
+		"HTTPProxy": "HTTP_PROXY",
+		"OAuth2Key": "O_AUTH2_KEY",
+		"Level1":    "LEVEL1",
+	}
+
+	for name, expected := range tests {
+		if got := toEnvSegment(name); got != expected {
+			t.Errorf("toEnvSegment(%q): expected %q, got %q", name, expected, got)
+		}
+	}
+}
+
+func TestSkipEnvTag(t *testing.T) {
+	t.Parallel()
+
+	type SkipConfig struct {
+		Name   string `yaml:"name" env:"-"`
+		Secret struct {
+			Key string `yaml:"key" env:"KEY"`
+		} `yaml:"secret" env:"-"`
+		Version string `yaml:"version"`
+	}
+
+	var cfg SkipConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("simple_config"),
+		WithEnvPrefix("TEST"),
+		WithAutoEnv(),
+		WithEnv(map[string]string{
+			"TEST_NAME":    "should-not-work",
+			"TEST_KEY":     "should-not-work",
+			"TEST_VERSION": "2.0.0",
+		}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Name != "simple-app" {
+		t.Errorf("Expected name 'simple-app' untouched by env, got '%s'", cfg.Name)
+	}
+
+	if cfg.Secret.Key != "" {
+		t.Errorf("Expected secret.key untouched by env, got '%s'", cfg.Secret.Key)
+	}
+
+	if cfg.Version != "2.0.0" {
+		t.Errorf("Expected version '2.0.0' from auto env, got '%s'", cfg.Version)
+	}
+}
+
+func TestLoadFromTomlFile(t *testing.T) {
+	t.Parallel()
+
+	type TomlConfig struct {
+		App struct {
+			Name string `toml:"name"`
+		} `toml:"app"`
+		Server struct {
+			Host string `toml:"host"`
+			Port int    `toml:"port" env:"SERVER_PORT"`
+		} `toml:"server"`
+	}
+
+	var cfg TomlConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("toml_config"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_SERVER_PORT": "9090"}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.App.Name != "toml-app" {
+		t.Errorf("Expected app.name 'toml-app', got '%s'", cfg.App.Name)
+	}
+
+	if cfg.Server.Host != "toml.localhost" {
+		t.Errorf("Expected server.host 'toml.localhost', got '%s'", cfg.Server.Host)
+	}
+
+	if cfg.Server.Port != 9090 {
+		t.Errorf("Expected server.port 9090 from env, got %d", cfg.Server.Port)
+	}
+
+	var strictCfg struct {
+		App struct {
+			Name string `toml:"name"`
+		} `toml:"app"`
+	}
+
+	err = Load(&strictCfg,
+		WithPaths("./test"),
+		WithName("toml_config"),
+		WithStrict(),
+	)
+
+	if err == nil {
+		t.Error("Expected error for unknown TOML keys in strict mode")
+	}
+}
+
+func TestIniConfig(t *testing.T) {
+	t.Parallel()
+
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("ini_config"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_SERVER_HOST": "env.localhost"}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.App.Name != "ini-app" || cfg.App.Version != "2.0.0" {
+		t.Errorf("Expected app ini-app 2.0.0, got %s %s", cfg.App.Name, cfg.App.Version)
+	}
+
+	// Повторный ключ переопределяет предыдущий
+	if cfg.Server.Port != 7001 || !cfg.Server.Debug {
+		t.Errorf("Expected server.port 7001 and debug, got %d and %v", cfg.Server.Port, cfg.Server.Debug)
+	}
+
+	if cfg.Server.Host != "env.localhost" {
+		t.Errorf("Expected server.host from env, got %s", cfg.Server.Host)
+	}
+
+	if cfg.Database.Host != "db.ini" || cfg.Database.Port != 5433 {
+		t.Errorf("Expected database db.ini:5433 from dotted keys, got %s:%d", cfg.Database.Host, cfg.Database.Port)
+	}
+}
+
+func TestPropertiesConfig(t *testing.T) {
+	t.Parallel()
+
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("props_config"),
+		WithEnv(map[string]string{}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.App.Name != "props-app" || cfg.Server.Host != "props.localhost" || cfg.Server.Port != 7100 {
+		t.Errorf("Expected values from properties file, got %+v %+v", cfg.App, cfg.Server)
+	}
+
+	if !cfg.Features.Enabled || cfg.Features.Timeout != 45 {
+		t.Errorf("Expected features enabled with timeout 45, got %+v", cfg.Features)
+	}
+
+	err = LoadReader(&cfg, strings.NewReader("server.port=1\nserver.port.extra=2\n"), WithFormat("properties"))
+
+	if err == nil || !strings.Contains(err.Error(), "both a value and a section") {
+		t.Errorf("Expected conflicting keys error, got %v", err)
+	}
+
+	err = LoadReader(&cfg, strings.NewReader("[server]\nport\n"), WithFormat("ini"))
+
+	if err == nil || !strings.Contains(err.Error(), "line 2: expected key=value") {
+		t.Errorf("Expected syntax error, got %v", err)
+	}
+}
+
+func TestEnvFileIndirection(t *testing.T) {
+	t.Parallel()
+
+	type SecretConfig struct {
+		Password string `yaml:"password" env:"DB_PASSWORD"`
+		Token    string `yaml:"token" env:"TOKEN"`
+	}
+
+	var cfg SecretConfig
+
+	err := Load(&cfg,
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{
+			"TEST_DB_PASSWORD_FILE": "./test/db_password.txt",
+			"TEST_TOKEN":            "direct",
+			"TEST_TOKEN_FILE":       "./test/db_password.txt",
+		}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Password != "s3cr3t" {
+		t.Errorf("Expected password 's3cr3t' from file, got '%s'", cfg.Password)
+	}
+
+	// Прямая переменная приоритетнее _FILE
+	if cfg.Token != "direct" {
+		t.Errorf("Expected token 'direct' from env, got '%s'", cfg.Token)
+	}
+
+	err = Load(&cfg,
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_DB_PASSWORD_FILE": "./test/whereIsMyMind"}),
+	)
+
+	if err == nil {
+		t.Error("Expected error for unreadable _FILE path")
+	}
+}
+
+func TestRequireFile(t *testing.T) {
+	t.Parallel()
+
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithPaths("./whereAreYou", "./test"),
+		WithName("missing"),
+		WithRequireFile(),
+	)
+
+	if err == nil {
+		t.Fatal("Expected error when required config file is missing")
+	}
+
+	for _, expected := range []string{`"missing"`, "whereAreYou", filepath.Join("test", "missing.yaml")} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected error to contain '%s', got: %v", expected, err)
+		}
+	}
+
+	err = Load(&cfg,
+		WithPaths("./whereAreYou", "./test"),
+		WithName("config"),
+		WithRequireFile(),
+		WithEnv(map[string]string{}),
+	)
+
+	if err != nil {
+		t.Errorf("Load failed with existing required file: %v", err)
+	}
+}
+
+func TestSources(t *testing.T) {
+	t.Parallel()
+
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithSource(Source{Name: "config", Paths: []string{"./test"}, Required: true}),
+		WithSource(Source{Name: "config_override", Paths: []string{"./test"}}),
+		WithSource(Source{Name: "missing", Paths: []string{"./test"}}),
+		WithEnv(map[string]string{}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Второй источник загружается поверх первого
+	if cfg.Server.Port != 8080 || cfg.Server.Host != "localhost" {
+		t.Errorf("Expected port 8080 and host localhost, got %d and %s", cfg.Server.Port, cfg.Server.Host)
+	}
+
+	if cfg.Database.Name != "test_db" {
+		t.Errorf("Expected database.name test_db from base source, got %s", cfg.Database.Name)
+	}
+
+	var missing TestConfig
+
+	err = Load(&missing,
+		WithSource(Source{Name: "config", Paths: []string{"./test"}}),
+		WithSource(Source{Name: "missing", Paths: []string{"./test"}, Required: true}),
+		WithEnv(map[string]string{}),
+	)
+
+	if !errors.Is(err, ErrConfigNotFound) {
+		t.Errorf("Expected ErrConfigNotFound for required source, got %v", err)
+	}
+}
+
+func TestPathsFromEnv(t *testing.T) {
+	t.Parallel()
+
+	var cfg TestConfig
+
+	paths := strings.Join([]string{"./whereAreYou", "", "./test/override"}, string(os.PathListSeparator))
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithPathsFromEnv("APP_CONFIG_PATHS"),
+		WithEnv(map[string]string{"APP_CONFIG_PATHS": paths}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Database.Name != "override_db" || cfg.App.Name != "" {
+		t.Errorf("Expected config from ./test/override only, got %+v %+v", cfg.App, cfg.Database)
+	}
+
+	// Пустая переменная не отменяет WithPaths
+	var fallback TestConfig
+
+	err = Load(&fallback,
+		WithPaths("./test"),
+		WithPathsFromEnv("APP_CONFIG_PATHS"),
+		WithEnv(map[string]string{"APP_CONFIG_PATHS": ""}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if fallback.App.Name != "test-app" {
+		t.Errorf("Expected app.name test-app from WithPaths, got %q", fallback.App.Name)
+	}
+}
+
+func TestConfigPathEnv(t *testing.T) {
+	t.Parallel()
+
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("config"),
+		WithConfigPathEnv("APP_CONFIG_FILE"),
+		WithEnv(map[string]string{"APP_CONFIG_FILE": "./test/json_config.json"}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.App.Name != "json-app" {
+		t.Errorf("Expected app.name 'json-app' from APP_CONFIG_FILE, got '%s'", cfg.App.Name)
+	}
+
+	err = Load(&cfg,
+		WithConfigPathEnv("APP_CONFIG_FILE"),
+		WithEnv(map[string]string{"APP_CONFIG_FILE": "./test/whereIsMyMind.yaml"}),
+	)
+
+	if err == nil {
+		t.Error("Expected error when APP_CONFIG_FILE points to a missing file")
+	}
+
+	var fallback TestConfig
+
+	err = Load(&fallback,
+		WithPaths("./test"),
+		WithName("config"),
+		WithConfigPathEnv("APP_CONFIG_FILE"),
+		WithEnv(map[string]string{}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if fallback.App.Name != "test-app" {
+		t.Errorf("Expected app.name 'test-app' from search paths, got '%s'", fallback.App.Name)
+	}
+}
+
+func TestParseBool(t *testing.T) {
+	tests := map[string]bool{
+		"true": true, "TRUE": true, "1": true, "t": true,
+		"yes": true, "Yes": true, "y": true, "ON": true, "enabled": true,
+		"false": false, "0": false, "F": false,
+		"no": false, "N": false, "off": false, "Disabled": false,
+	}
+
+	for value, expected := range tests {
+		got, err := parseBool(value)
+		if err != nil {
+			t.Errorf("parseBool(%q) failed: %v", value, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("parseBool(%q): expected %t, got %t", value, expected, got)
+		}
+	}
+
+	if _, err := parseBool("maybe"); err == nil {
+		t.Error("Expected error for 'maybe'")
+	}
+}
+
+func TestPointerScalarsFromEnv(t *testing.T) {
+	t.Parallel()
+
+	type PointerConfig struct {
+		Port  *int    `yaml:"port" env:"PORT"`
+		Debug *bool   `yaml:"debug" env:"DEBUG"`
+		Name  *string `yaml:"name" env:"NAME"`
+		Limit *int    `yaml:"limit" env:"LIMIT"`
+	}
+
+	var cfg PointerConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{
+			"TEST_PORT":  "8080",
+			"TEST_DEBUG": "false",
+			"TEST_NAME":  "",
+		}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Port == nil || *cfg.Port != 8080 {
+		t.Errorf("Expected port 8080 from env, got %v", cfg.Port)
+	}
+
+	// false из env отличается от незаданного значения
+	if cfg.Debug == nil || *cfg.Debug {
+		t.Errorf("Expected debug set to false, got %v", cfg.Debug)
+	}
+
+	if cfg.Name == nil || *cfg.Name != "" {
+		t.Errorf("Expected name set to empty string, got %v", cfg.Name)
+	}
+
+	if cfg.Limit != nil {
+		t.Errorf("Expected limit to stay nil, got %d", *cfg.Limit)
+	}
+
+	err = Load(&cfg,
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_PORT": "abc"}),
+	)
+
+	if err == nil {
+		t.Error("Expected error for invalid pointer value")
+	}
+}
+
+func TestTimeFromEnv(t *testing.T) {
+	t.Parallel()
+
+	type TimeConfig struct {
+		StartAt time.Time  `yaml:"start_at" env:"START_AT"`
+		Cutoff  time.Time  `yaml:"cutoff" env:"CUTOFF" format:"2006-01-02"`
+		EndAt   *time.Time `yaml:"end_at" env:"END_AT"`
+	}
+
+	var cfg TimeConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{
+			"TEST_START_AT": "2024-03-01T10:30:00Z",
+			"TEST_CUTOFF":   "2024-12-31",
+			"TEST_END_AT":   "2024-03-02T00:00:00Z",
+		}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if expected := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC); !cfg.StartAt.Equal(expected) {
+		t.Errorf("Expected start_at %s, got %s", expected, cfg.StartAt)
+	}
+
+	if expected := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC); !cfg.Cutoff.Equal(expected) {
+		t.Errorf("Expected cutoff %s, got %s", expected, cfg.Cutoff)
+	}
+
+	if cfg.EndAt == nil || !cfg.EndAt.Equal(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected end_at 2024-03-02, got %v", cfg.EndAt)
+	}
+
+	err = Load(&cfg,
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_CUTOFF": "31.12.2024"}),
+	)
+
+	if err == nil {
+		t.Fatal("Expected error for invalid time value")
+	}
+
+	if !strings.Contains(err.Error(), `expected layout "2006-01-02"`) {
+		t.Errorf("Expected error to mention the layout, got %v", err)
+	}
+}
+
+func TestArrayFromEnv(t *testing.T) {
+	t.Parallel()
+
+	type ArrayConfig struct {
+		Ports [3]int `yaml:"ports" env:"PORTS"`
+	}
+
+	var cfg ArrayConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_PORTS": "80,443,8080"}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Ports != [3]int{80, 443, 8080} {
+		t.Errorf("Expected ports [80 443 8080], got %v", cfg.Ports)
+	}
+
+	err = Load(&cfg,
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_PORTS": "80,443"}),
+	)
+
+	if err == nil {
+		t.Fatal("Expected error for wrong number of elements")
+	}
+
+	if !strings.Contains(err.Error(), "expected 3 elements, got 2") {
+		t.Errorf("Expected element count in error, got %v", err)
+	}
+}
+
+func TestUnexportedFieldsLeftAlone(t *testing.T) {
+	t.Parallel()
+
+	type MixedConfig struct {
+		Name    string `yaml:"name" env:"NAME" default:"app"`
+		secret  string `env:"SECRET" default:"hidden"`
+		inner   testHidden
+		Port    int        `yaml:"port" env:"PORT"`
+		counter *int       `env:"COUNTER"`
+		Server  TestServer `yaml:"server"`
+	}
+
+	var cfg MixedConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithAutoEnv(),
+		WithExpandEnv(),
+		WithEnv(map[string]string{
+			"TEST_NAME":        "env-name",
+			"TEST_SECRET":      "leaked",
+			"TEST_REGION":      "eu",
+			"TEST_PORT":        "8080",
+			"TEST_COUNTER":     "1",
+			"TEST_SERVER_HOST": "env-host",
+		}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.secret != "" || cfg.inner.Region != "" || cfg.counter != nil {
+		t.Errorf("Expected unexported fields to stay zero, got %q, %q, %v", cfg.secret, cfg.inner.Region, cfg.counter)
+	}
+
+	// Экспортируемые поля рядом с неэкспортируемыми переопределяются как обычно
+	if cfg.Name != "env-name" || cfg.Port != 8080 || cfg.Server.Host != "env-host" {
+		t.Errorf("Expected exported fields from env, got name %q, port %d, server.host %q",
+			cfg.Name, cfg.Port, cfg.Server.Host)
+	}
+}
+
+func TestCaseInsensitiveEnv(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{
+		"test_Server_Port": "9090",
+		"TEST_SERVER_HOST": "exact-host",
+		"Test_Labels_Team": "core",
+	}
+
+	type CaseConfig struct {
+		Server TestServer        `yaml:"server"`
+		Labels map[string]string `yaml:"labels" env:"LABELS"`
+	}
+
+	var cfg CaseConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(env),
+		WithCaseInsensitiveEnv(),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Server.Port != 9090 {
+		t.Errorf("Expected server.port 9090 from test_Server_Port, got %d", cfg.Server.Port)
+	}
+
+	if cfg.Server.Host != "exact-host" {
+		t.Errorf("Expected server.host exact-host, got %s", cfg.Server.Host)
+	}
+
+	if cfg.Labels["team"] != "core" {
+		t.Errorf("Expected labels.team core, got %v", cfg.Labels)
+	}
+
+	// Без опции имена сравниваются с учетом регистра
+	var strict CaseConfig
+
+	err = Load(&strict,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(env),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if strict.Server.Port != 0 {
+		t.Errorf("Expected server.port to stay 0 without the option, got %d", strict.Server.Port)
+	}
+}
+
+func TestNameFromExecutable(t *testing.T) {
+	t.Parallel()
+
+	// Тестовый бинарник называется cfg.test, значит ищется cfg.yaml
+	base := filepath.Base(os.Args[0])
+	name := strings.TrimSuffix(base, filepath.Ext(base))
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, name+".yaml"), []byte("app:\n  name: from-binary\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	var cfg TestConfig
+	err := Load(&cfg,
+		WithPaths(dir),
+		WithNameFromExecutable(),
+		WithEnv(map[string]string{}),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.App.Name != "from-binary" {
+		t.Errorf("Expected app.name from-binary from %s.yaml, got %q", name, cfg.App.Name)
+	}
+}
+
+func TestMultipleNames(t *testing.T) {
+	t.Parallel()
+
+	var first TestConfig
+
+	err := Load(&first,
+		WithPaths("./test"),
+		WithNames("missing", "config_override", "config"),
+		WithEnv(map[string]string{}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Без WithMerge используется первый найденный файл
+	if first.App.Name != "override-app" || first.App.Version != "" {
+		t.Errorf("Expected only config_override.yaml to be loaded, got app %+v", first.App)
+	}
+
+	var merged TestConfig
+
+	err = Load(&merged,
+		WithPaths("./test"),
+		WithNames("config", "config_override"),
+		WithMerge(),
+		WithEnv(map[string]string{}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if merged.App.Name != "override-app" || merged.App.Version != "1.0.0" {
+		t.Errorf("Expected config_override.yaml layered over config.yaml, got app %+v", merged.App)
+	}
+
+	var none TestConfig
+
+	err = Load(&none,
+		WithPaths("./test"),
+		WithName("config"),
+		WithNames("missing", "absent"),
+		WithEnv(map[string]string{}),
+	)
+
+	if err != nil {
+		t.Errorf("Expected no error when no file is found, got %v", err)
+	}
+
+	if none.App.Name != "" {
+		t.Errorf("Expected WithNames to replace WithName, got app.name '%s'", none.App.Name)
+	}
+}
+
+func TestLoadContext(t *testing.T) {
+	t.Parallel()
+
+	var cfg TestConfig
+
+	err := LoadContext(context.Background(), &cfg,
+		WithPaths("./test"),
+		WithName("config"),
+		WithEnv(map[string]string{}),
+	)
+
+	if err != nil {
+		t.Fatalf("LoadContext failed: %v", err)
+	}
+
+	if cfg.App.Name != "test-app" {
+		t.Errorf("Expected app.name 'test-app', got '%s'", cfg.App.Name)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var canceled TestConfig
+
+	err = LoadContext(ctx, &canceled,
+		WithPaths("./test"),
+		WithName("config"),
+		WithEnv(map[string]string{}),
+	)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	if canceled.App.Name != "" {
+		t.Errorf("Expected nothing loaded after cancel, got app.name '%s'", canceled.App.Name)
+	}
+}
+
+func TestYamlAnchorsAndMergeKeys(t *testing.T) {
+	t.Parallel()
+
+	type Endpoint struct {
+		Host string `yaml:"host" env:"HOST"`
+		Port int    `yaml:"port" env:"PORT"`
+		User string `yaml:"user"`
+		Name string `yaml:"name"`
+	}
+	type AnchorConfig struct {
+		Defaults Endpoint `yaml:"defaults"`
+		App      TestApp  `yaml:"app"`
+		Server   Endpoint `yaml:"server"`
+		Database Endpoint `yaml:"database"`
+		Features struct {
+			Labels      map[string]string `yaml:"labels"`
+			Annotations map[string]string `yaml:"annotations"`
+		} `yaml:"features"`
+	}
+
+	for _, strict := range []bool{false, true} {
+		actions := []Action{
+			WithPaths("./test"),
+			WithName("anchors_config"),
+			WithEnvPrefix("TEST"),
+			WithEnv(map[string]string{"TEST_PORT": "9999"}),
+		}
+		if strict {
+			actions = append(actions, WithStrict())
+		}
+
+		var cfg AnchorConfig
+		if err := Load(&cfg, actions...); err != nil {
+			t.Fatalf("Load failed (strict=%t): %v", strict, err)
+		}
+
+		// Алиас скалярного значения
+		if cfg.App.Version != "anchor-app" {
+			t.Errorf("Expected app.version 'anchor-app' from alias, got '%s'", cfg.App.Version)
+		}
+
+		// Merge key в структуру, локальные ключи важнее
+		if cfg.Server.Host != "shared.localhost" || cfg.Server.User != "admin" {
+			t.Errorf("Expected server to inherit defaults, got %+v", cfg.Server)
+		}
+
+		if cfg.Database.Name != "anchors_db" || cfg.Database.Host != "shared.localhost" {
+			t.Errorf("Expected database to inherit defaults, got %+v", cfg.Database)
+		}
+
+		// env применяется поверх значений из merge key
+		if cfg.Server.Port != 9999 || cfg.Database.Port != 9999 {
+			t.Errorf("Expected env port 9999 over merged values, got %d and %d", cfg.Server.Port, cfg.Database.Port)
+		}
+
+		// Merge key в map
+		if !reflect.DeepEqual(cfg.Features.Annotations, map[string]string{"team": "core", "owner": "ops"}) {
+			t.Errorf("Expected annotations merged with labels, got %v", cfg.Features.Annotations)
+		}
+	}
+}
+
+func TestWithoutEnv(t *testing.T) {
+	t.Parallel()
+
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("config"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{
+			"TEST_SERVER_PORT": "9999",
+			"TEST_APP_NAME":    "env-app",
+		}),
+		WithoutEnv(),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Server.Port != 3000 || cfg.App.Name != "test-app" {
+		t.Errorf("Expected values from file only, got server.port %d, app.name '%s'", cfg.Server.Port, cfg.App.Name)
+	}
+}
+
+func TestWithoutFile(t *testing.T) {
+	t.Parallel()
+
+	type EnvOnlyConfig struct {
+		Server   TestServer `yaml:"server"`
+		Password string     `yaml:"password" required:"true"`
+		Region   string     `yaml:"region" default:"eu"`
+	}
+
+	var cfg EnvOnlyConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("config"),
+		WithEnvPrefix("TEST"),
+		WithAutoEnv(),
+		WithEnv(map[string]string{
+			"TEST_SERVER_PORT": "9999",
+			"TEST_PASSWORD":    "secret",
+		}),
+		WithoutFile(),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// test/config.yaml не читается
+	if cfg.Server.Host != "" || cfg.Server.Port != 9999 || cfg.Region != "eu" {
+		t.Errorf("Expected only env and defaults, got %+v, region '%s'", cfg.Server, cfg.Region)
+	}
+
+	var missing EnvOnlyConfig
+
+	err = Load(&missing,
+		WithEnvPrefix("TEST"),
+		WithAutoEnv(),
+		WithEnv(map[string]string{}),
+		WithoutFile(),
+	)
+
+	if !errors.Is(err, ErrRequired) {
+		t.Errorf("Expected required error without env, got %v", err)
+	}
+}
+
+func TestTextUnmarshalerSliceFromEnv(t *testing.T) {
+	t.Parallel()
+
+	type SliceConfig struct {
+		Timeouts []time.Duration `yaml:"timeouts" env:"TIMEOUTS"`
+		IPs      []net.IP        `yaml:"ips" env:"IPS"`
+		Levels   []testLevel     `yaml:"levels" env:"LEVELS"`
+	}
+
+	var cfg SliceConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{
+			"TEST_TIMEOUTS": "1s,2s,500ms",
+			"TEST_IPS":      "10.0.0.1,::1",
+			"TEST_LEVELS":   "high",
+		}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 500 * time.Millisecond}
+	if !reflect.DeepEqual(cfg.Timeouts, expected) {
+		t.Errorf("Expected timeouts %v, got %v", expected, cfg.Timeouts)
+	}
+
+	if len(cfg.IPs) != 2 || !cfg.IPs[0].Equal(net.ParseIP("10.0.0.1")) || !cfg.IPs[1].Equal(net.IPv6loopback) {
+		t.Errorf("Expected ips [10.0.0.1 ::1], got %v", cfg.IPs)
+	}
+
+	if len(cfg.Levels) != 1 || cfg.Levels[0].Value != 2 {
+		t.Errorf("Expected levels [high], got %v", cfg.Levels)
+	}
+
+	err = Load(&cfg,
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_IPS": "10.0.0.1,nope"}),
+	)
+
+	if err == nil || !strings.Contains(err.Error(), "element 1") {
+		t.Errorf("Expected error for element 1, got %v", err)
+	}
+}
+
+func TestURLFromEnv(t *testing.T) {
+	t.Parallel()
+
+	type URLConfig struct {
+		BaseURL  url.URL  `yaml:"base_url" env:"BASE_URL"`
+		Proxy    *url.URL `yaml:"proxy" env:"PROXY"`
+		Fallback *url.URL `yaml:"fallback" env:"FALLBACK"`
+	}
+
+	var cfg URLConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{
+			"TEST_BASE_URL": "https://api.example.com/v1?debug=1",
+			"TEST_PROXY":    "http://proxy.local:3128",
+		}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.BaseURL.Host != "api.example.com" || cfg.BaseURL.Path != "/v1" || cfg.BaseURL.Query().Get("debug") != "1" {
+		t.Errorf("Expected parsed base_url, got %s", cfg.BaseURL.String())
+	}
+
+	if cfg.Proxy == nil || cfg.Proxy.Port() != "3128" {
+		t.Errorf("Expected proxy with port 3128, got %v", cfg.Proxy)
+	}
+
+	if cfg.Fallback != nil {
+		t.Errorf("Expected fallback to stay nil, got %v", cfg.Fallback)
+	}
+
+	err = Load(&cfg,
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_BASE_URL": "http://bad host"}),
+	)
+
+	if err == nil || !strings.Contains(err.Error(), "field BaseURL") {
+		t.Errorf("Expected error naming BaseURL, got %v", err)
+	}
+}
+
+func TestEnvNoPrefixOption(t *testing.T) {
+	t.Parallel()
+
+	type NoPrefixConfig struct {
+		DatabaseURL string     `yaml:"database_url" env:"DATABASE_URL,noprefix"`
+		Server      TestServer `yaml:"server"`
+	}
+
+	var cfg NoPrefixConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{
+			"DATABASE_URL":      "postgres://db/app",
+			"TEST_DATABASE_URL": "postgres://prefixed/app",
+			"TEST_SERVER_PORT":  "8080",
+		}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.DatabaseURL != "postgres://db/app" {
+		t.Errorf("Expected database_url from unprefixed DATABASE_URL, got '%s'", cfg.DatabaseURL)
+	}
+
+	if cfg.Server.Port != 8080 {
+		t.Errorf("Expected other fields to keep the prefix, got server.port %d", cfg.Server.Port)
+	}
+
+	if names := EnvVars(&cfg, WithEnvPrefix("TEST")); names[0] != "DATABASE_URL" {
+		t.Errorf("Expected EnvVars to list DATABASE_URL, got %v", names)
+	}
+}
+
+func TestNameEnv(t *testing.T) {
+	t.Parallel()
+
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("config"),
+		WithNameEnv("APP_CONFIG_NAME"),
+		WithEnv(map[string]string{"APP_CONFIG_NAME": "json_config"}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.App.Name != "json-app" {
+		t.Errorf("Expected app.name 'json-app' from APP_CONFIG_NAME, got '%s'", cfg.App.Name)
+	}
+
+	var fallback TestConfig
+
+	err = Load(&fallback,
+		WithPaths("./test"),
+		WithName("config"),
+		WithNameEnv("APP_CONFIG_NAME"),
+		WithEnv(map[string]string{}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if fallback.App.Name != "test-app" {
+		t.Errorf("Expected app.name 'test-app' from WithName, got '%s'", fallback.App.Name)
+	}
+}
+
+func TestSliceOfStructsFromEnv(t *testing.T) {
+	t.Parallel()
+
+	type Upstream struct {
+		Host string `yaml:"host" env:"HOST"`
+		Port int    `yaml:"port" env:"PORT"`
+	}
+	type SliceConfig struct {
+		Servers []Upstream `yaml:"servers" env:"SERVERS"`
+		Mirrors []Upstream `yaml:"mirrors"`
+	}
+
+	data := `
+servers:
+  - host: a.local
+    port: 80
+  - host: b.local
+    port: 81
+mirrors:
+  - host: m.local
+`
+
+	var cfg SliceConfig
+
+	err := LoadReader(&cfg, strings.NewReader(data),
+		WithEnvPrefix("TEST"),
+		WithAutoEnv(),
+		WithEnv(map[string]string{
+			"TEST_SERVERS_1_PORT": "9090",
+			"TEST_SERVERS_2_PORT": "9191",
+			"TEST_MIRRORS_0_HOST": "env-mirror",
+		}),
+	)
+
+	if err != nil {
+		t.Fatalf("LoadReader failed: %v", err)
+	}
+
+	if cfg.Servers[0].Port != 80 || cfg.Servers[1].Port != 9090 || cfg.Servers[1].Host != "b.local" {
+		t.Errorf("Expected only servers[1].port overridden, got %+v", cfg.Servers)
+	}
+
+	// Срез не растет из env
+	if len(cfg.Servers) != 2 {
+		t.Errorf("Expected 2 servers, got %d", len(cfg.Servers))
+	}
+
+	// С WithAutoEnv индекс добавляется к имени из пути
+	if cfg.Mirrors[0].Host != "env-mirror" {
+		t.Errorf("Expected mirrors[0].host from TEST_MIRRORS_0_HOST, got '%s'", cfg.Mirrors[0].Host)
+	}
+
+	err = LoadReader(&cfg, strings.NewReader(data),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_SERVERS_0_PORT": "abc"}),
+	)
+
+	if err == nil || !strings.Contains(err.Error(), "element 0") {
+		t.Errorf("Expected error for element 0, got %v", err)
+	}
+}
+
+func TestPrefixSeparator(t *testing.T) {
+	t.Parallel()
+
+	type SeparatorConfig struct {
+		Server struct {
+			MaxConns int `yaml:"max_conns"`
+		} `yaml:"server"`
+		Name   string            `yaml:"name" env:"APP_NAME"`
+		Labels map[string]string `yaml:"labels"`
+	}
+
+	var cfg SeparatorConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithAutoEnv(),
+		WithPrefixSeparator("__"),
+		WithEnv(map[string]string{
+			"TEST__SERVER__MAX_CONNS": "100",
+			"TEST__APP_NAME":          "dotnet-style",
+			"TEST__LABELS__TEAM":      "core",
+			"TEST_SERVER_MAX_CONNS":   "1",
+		}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Server.MaxConns != 100 {
+		t.Errorf("Expected server.max_conns 100 from TEST__SERVER__MAX_CONNS, got %d", cfg.Server.MaxConns)
+	}
+
+	if cfg.Name != "dotnet-style" {
+		t.Errorf("Expected name from TEST__APP_NAME, got '%s'", cfg.Name)
+	}
+
+	if cfg.Labels["team"] != "core" {
+		t.Errorf("Expected labels.team from TEST__LABELS__TEAM, got %v", cfg.Labels)
+	}
+}
+
+func TestBytesFromEnv(t *testing.T) {
+	t.Parallel()
+
+	type BytesConfig struct {
+		SigningKey []byte `yaml:"signing_key" env:"SIGNING_KEY"`
+		Salt       []byte `yaml:"salt" env:"SALT" format:"hex"`
+	}
+
+	var cfg BytesConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{
+			"TEST_SIGNING_KEY": "c2VjcmV0LWtleQ==",
+			"TEST_SALT":        "deadbeef",
+		}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if string(cfg.SigningKey) != "secret-key" {
+		t.Errorf("Expected signing_key 'secret-key', got %q", cfg.SigningKey)
+	}
+
+	if !reflect.DeepEqual(cfg.Salt, []byte{0xde, 0xad, 0xbe, 0xef}) {
+		t.Errorf("Expected salt deadbeef, got %x", cfg.Salt)
+	}
+
+	for env, field := range map[string]string{"TEST_SIGNING_KEY": "SigningKey", "TEST_SALT": "Salt"} {
+		err = Load(&cfg,
+			WithName("missing"),
+			WithEnvPrefix("TEST"),
+			WithEnv(map[string]string{env: "not valid!"}),
+		)
+
+		if err == nil || !strings.Contains(err.Error(), "field "+field) {
+			t.Errorf("Expected decode error naming %s, got %v", field, err)
+		}
+	}
+}
+
+func TestInvertEnv(t *testing.T) {
+	t.Parallel()
+
+	type InvertConfig struct {
+		CacheEnabled bool `yaml:"cache_enabled" default:"true" env:"DISABLE_CACHE,invert"`
+		DebugEnabled bool `yaml:"debug_enabled" env:"NO_DEBUG,invert"`
+	}
+
+	var cfg InvertConfig
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_DISABLE_CACHE": "true", "TEST_NO_DEBUG": "false"}),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// DISABLE_CACHE=true выключает кэш, NO_DEBUG=false включает отладку
+	if cfg.CacheEnabled || !cfg.DebugEnabled {
+		t.Errorf("Expected inverted values, got %+v", cfg)
+	}
+
+	type InvalidConfig struct {
+		Port int `yaml:"port" env:"NO_PORT,invert"`
+	}
+
+	var invalid InvalidConfig
+	err = Load(&invalid,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{}),
+	)
+
+	var envErr *EnvError
+	if !errors.As(err, &envErr) || envErr.Field != "Port" {
+		t.Errorf("Expected EnvError for invert on int field, got %v", err)
+	}
+}
+
+func TestSkipEmptyEnv(t *testing.T) {
+	t.Parallel()
+
+	type SkipConfig struct {
+		App struct {
+			Name    string `yaml:"name" env:"APP_NAME,skipempty"`
+			Version string `yaml:"version" env:"APP_VERSION"`
+		} `yaml:"app"`
+		Labels map[string]string `yaml:"labels" env:"LABELS,skipempty"`
+	}
+
+	env := map[string]string{
+		"TEST_APP_NAME":    "",
+		"TEST_APP_VERSION": "",
+		"TEST_LABELS_TEAM": "",
+		"TEST_LABELS_ENV":  "prod",
+	}
+
+	var cfg SkipConfig
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithEnvPrefix("TEST"),
+		WithEnv(env),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Пустая переменная с skipempty не затирает значение из файла, без него — затирает
+	if cfg.App.Name != "test-app" {
+		t.Errorf("Expected app.name test-app, got %q", cfg.App.Name)
+	}
+	if cfg.App.Version != "" {
+		t.Errorf("Expected empty app.version, got %q", cfg.App.Version)
+	}
+
+	// skipempty на map пропускает пустые ключи
+	if _, ok := cfg.Labels["team"]; ok || cfg.Labels["env"] != "prod" {
+		t.Errorf("Expected only labels.env from env, got %v", cfg.Labels)
+	}
+
+	var global SkipConfig
+	err = Load(&global,
+		WithPaths("./test"),
+		WithEnvPrefix("TEST"),
+		WithEnv(env),
+		WithIgnoreEmptyEnv(),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if global.App.Version != "1.0.0" {
+		t.Errorf("Expected app.version 1.0.0 with WithIgnoreEmptyEnv, got %q", global.App.Version)
+	}
+}
+
+func TestTrimSpaceEnv(t *testing.T) {
+	t.Parallel()
+
+	type TrimConfig struct {
+		Server TestServer        `yaml:"server"`
+		Labels map[string]string `yaml:"labels" env:"LABELS"`
+	}
+
+	env := map[string]string{
+		"TEST_SERVER_PORT": "8080\n",
+		"TEST_SERVER_HOST": "  example.com ",
+		"TEST_LABELS_TEAM": " core\t",
+	}
+
+	var cfg TrimConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(env),
+		WithTrimSpace(),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Server.Port != 8080 || cfg.Server.Host != "example.com" || cfg.Labels["team"] != "core" {
+		t.Errorf("Expected trimmed values, got %+v, labels %v", cfg.Server, cfg.Labels)
+	}
+
+	// Без опции пробелы сохраняются
+	err = Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(env),
+	)
+
+	if err == nil {
+		t.Error("Expected error for port with trailing newline without WithTrimSpace")
+	}
+}
+
+func TestEnvTagVerbatim(t *testing.T) {
+	t.Parallel()
+
+	type ProxyConfig struct {
+		Proxy string `yaml:"proxy" env:"http_proxy,noprefix"`
+		Mode  string `yaml:"mode" env:"runMode"`
+	}
+
+	env := map[string]string{
+		"http_proxy":   "http://lower:3128",
+		"HTTP_PROXY":   "http://upper:3128",
+		"TEST_runMode": "verbatim",
+		"TEST_RUNMODE": "upper",
+	}
+
+	var cfg ProxyConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(env),
+		WithEnvTagVerbatim(),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Proxy != "http://lower:3128" || cfg.Mode != "verbatim" {
+		t.Errorf("Expected values from verbatim names, got %s and %s", cfg.Proxy, cfg.Mode)
+	}
+
+	// По умолчанию тег приводится к верхнему регистру
+	var upper ProxyConfig
+
+	err = Load(&upper,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(env),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if upper.Proxy != "http://upper:3128" || upper.Mode != "upper" {
+		t.Errorf("Expected values from uppercased names, got %s and %s", upper.Proxy, upper.Mode)
+	}
+}
+
+func TestResetBeforeLoad(t *testing.T) {
+	t.Parallel()
+
+	type ResetConfig struct {
+		Hosts  []string          `yaml:"hosts"`
+		Labels map[string]string `yaml:"labels"`
+	}
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	write := func(data string) {
+		if err := os.WriteFile(file, []byte(data), 0o600); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+	}
+
+	write("hosts: [a, b, c]\nlabels:\n  team: core\n  tier: web\n")
+
+	var cfg ResetConfig
+	if err := Load(&cfg, WithPaths(dir), WithEnv(map[string]string{})); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	write("hosts: [d]\nlabels:\n  team: infra\n")
+
+	if err := Load(&cfg, WithPaths(dir), WithEnv(map[string]string{}), WithReset()); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if len(cfg.Hosts) != 1 || cfg.Hosts[0] != "d" {
+		t.Errorf("Expected hosts [d] after reset, got %v", cfg.Hosts)
+	}
+
+	// Без сброса в map остались бы ключи от предыдущей загрузки
+	if len(cfg.Labels) != 1 || cfg.Labels["team"] != "infra" {
+		t.Errorf("Expected labels map[team:infra] after reset, got %v", cfg.Labels)
+	}
+}
+
+func TestDurationCollectionsFromEnv(t *testing.T) {
+	t.Parallel()
+
+	type DurationsConfig struct {
+		Timeouts map[string]time.Duration   `yaml:"timeouts" env:"TIMEOUTS"`
+		Retries  map[string][]time.Duration `yaml:"retries" env:"RETRIES"`
+		Backoff  [2]time.Duration           `yaml:"backoff" env:"BACKOFF"`
+		Deadline *time.Duration             `yaml:"deadline" env:"DEADLINE"`
+	}
+
+	var cfg DurationsConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{
+			"TEST_TIMEOUTS_READ":  "5s",
+			"TEST_TIMEOUTS_WRITE": "1m30s",
+			"TEST_RETRIES_DB":     "100ms,1s",
+			"TEST_BACKOFF":        "1s,10s",
+			"TEST_DEADLINE":       "2h",
+		}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	expected := map[string]time.Duration{"read": 5 * time.Second, "write": 90 * time.Second}
+	if !reflect.DeepEqual(cfg.Timeouts, expected) {
+		t.Errorf("Expected timeouts %v, got %v", expected, cfg.Timeouts)
+	}
+
+	if !reflect.DeepEqual(cfg.Retries["db"], []time.Duration{100 * time.Millisecond, time.Second}) {
+		t.Errorf("Expected retries.db [100ms 1s], got %v", cfg.Retries["db"])
+	}
+
+	if cfg.Backoff != [2]time.Duration{time.Second, 10 * time.Second} {
+		t.Errorf("Expected backoff [1s 10s], got %v", cfg.Backoff)
+	}
+
+	if cfg.Deadline == nil || *cfg.Deadline != 2*time.Hour {
+		t.Errorf("Expected deadline 2h, got %v", cfg.Deadline)
+	}
+
+	// Ошибка разбора значения map указывает ключ
+	err = Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_TIMEOUTS_READ": "soon"}),
+	)
+
+	if err == nil || !strings.Contains(err.Error(), `key TEST_TIMEOUTS_READ: invalid duration "soon"`) {
+		t.Errorf("Expected map value duration error, got %v", err)
+	}
+}
+
+func TestConvertString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value    string
+		expected any
+	}{
+		{"text", "text"},
+		{"-42", int8(-42)},
+		{"42", uint16(42)},
+		{"1.5", 1.5},
+		{"yes", true},
+		{"1m30s", 90 * time.Second},
+		{"2024-03-01T10:30:00Z", time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)},
+		{"aGVsbG8=", []byte("hello")},
+		{"10.0.0.1", net.ParseIP("10.0.0.1")},
+		{"https://example.com/x", url.URL{Scheme: "https", Host: "example.com", Path: "/x"}},
+	}
+
+	for _, tt := range tests {
+		typ := reflect.TypeOf(tt.expected)
+		got, err := convertString(typ, tt.value)
+		if err != nil {
+			t.Errorf("convertString(%s, %q) failed: %v", typ, tt.value, err)
+			continue
+		}
+
+		if got.Type() != typ || !reflect.DeepEqual(got.Interface(), tt.expected) {
+			t.Errorf("Expected convertString(%s, %q) = %v, got %v", typ, tt.value, tt.expected, got)
+		}
+	}
+}
+
+func TestConvertStringErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		typ      reflect.Type
+		value    string
+		expected string
+	}{
+		{reflect.TypeOf(int8(0)), "300", `value "300" is out of range for int8`},
+		{reflect.TypeOf(0), "abc", `value "abc" is not a valid int`},
+		{reflect.TypeOf(time.Duration(0)), "soon", `invalid duration "soon"`},
+		{reflect.TypeOf([]byte(nil)), "!!", "invalid base64 value"},
+		{reflect.TypeOf(struct{}{}), "x", "unsupported type: struct"},
+	}
+
+	for _, tt := range tests {
+		_, err := convertString(tt.typ, tt.value)
+		if err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("Expected error containing %q for %s %q, got %v", tt.expected, tt.typ, tt.value, err)
+		}
+	}
+}
+
+func TestYamlEnvNames(t *testing.T) {
+	t.Parallel()
+
+	type Pool struct {
+		MaxConns int    `yaml:"max-conns,omitempty"`
+		Timeout  string `yaml:"idle_timeout"`
+		Name     string
+	}
+	type YamlNamesConfig struct {
+		DB   Pool   `yaml:"database"`
+		Mode string `yaml:"mode" env:"RUN_MODE"`
+	}
+
+	var cfg YamlNamesConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithAutoEnv(),
+		WithYamlEnvNames(),
+		WithEnv(map[string]string{
+			"TEST_DATABASE_MAX_CONNS":    "20",
+			"TEST_DATABASE_IDLE_TIMEOUT": "5m",
+			"TEST_DATABASE_NAME":         "main",
+			"TEST_DB_MAX_CONNS":          "1",
+			"TEST_RUN_MODE":              "fast",
+		}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.DB.MaxConns != 20 || cfg.DB.Timeout != "5m" {
+		t.Errorf("Expected names from yaml tags, got %+v", cfg.DB)
+	}
+
+	// Поле без yaml тега и явный env тег работают как раньше
+	if cfg.DB.Name != "main" || cfg.Mode != "fast" {
+		t.Errorf("Expected name main and mode fast, got %s and %s", cfg.DB.Name, cfg.Mode)
+	}
+
+	expected := []string{"TEST_DATABASE_MAX_CONNS", "TEST_DATABASE_IDLE_TIMEOUT", "TEST_DATABASE_NAME", "TEST_RUN_MODE"}
+	if names := EnvVars(&cfg, WithEnvPrefix("TEST"), WithAutoEnv(), WithYamlEnvNames()); !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected EnvVars %v, got %v", expected, names)
+	}
+}
+
+// testNode ссылается на свой же тип, обходы не должны аллоцировать его бесконечно
+type testNode struct {
+	Name     string    `yaml:"name" env:"NAME" flag:"name"`
+	Fallback *testNode `yaml:"fallback"`
+}
+
+func TestRecursiveTypeFromEnv(t *testing.T) {
+	t.Parallel()
+
+	var cfg testNode
+	err := Load(&cfg,
+		WithoutFile(),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_NAME": "primary"}),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Name != "primary" || cfg.Fallback != nil {
+		t.Errorf("Expected name primary without fallback, got %+v", cfg)
+	}
+
+	// Существующая цепочка из файла обходится целиком
+	chain := testNode{Fallback: &testNode{Fallback: &testNode{}}}
+	err = Load(&chain,
+		WithoutFile(),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_NAME": "all"}),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if chain.Fallback.Fallback.Name != "all" || chain.Fallback.Fallback.Fallback != nil {
+		t.Errorf("Expected existing chain to be overridden, got %+v", chain.Fallback.Fallback)
+	}
+}
+
+func TestPointerChainsFromEnv(t *testing.T) {
+	t.Parallel()
+
+	type ServerConfig struct {
+		Host string `yaml:"host" env:"HOST"`
+		Port int    `yaml:"port" env:"PORT"`
+	}
+	type ChainConfig struct {
+		Servers []*ServerConfig `yaml:"servers" env:"SERVERS"`
+		Primary **ServerConfig  `yaml:"primary"`
+	}
+
+	first := &ServerConfig{Host: "a", Port: 1}
+	cfg := ChainConfig{Servers: []*ServerConfig{first, nil, {Host: "c", Port: 3}}}
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{
+			"TEST_SERVERS_0_PORT": "8080",
+			"TEST_SERVERS_1_HOST": "b",
+			"TEST_HOST":           "primary",
+		}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Существующий элемент обновляется на месте
+	if cfg.Servers[0] != first || first.Port != 8080 || first.Host != "a" {
+		t.Errorf("Expected servers[0] a:8080 updated in place, got %+v", cfg.Servers[0])
+	}
+
+	// Nil-элемент создается, только если задана его переменная
+	if cfg.Servers[1] == nil || cfg.Servers[1].Host != "b" {
+		t.Errorf("Expected servers[1] allocated with host b, got %+v", cfg.Servers[1])
+	}
+
+	if cfg.Servers[2].Port != 3 {
+		t.Errorf("Expected servers[2] untouched, got %+v", cfg.Servers[2])
+	}
+
+	if cfg.Primary == nil || *cfg.Primary == nil || (*cfg.Primary).Host != "primary" {
+		t.Errorf("Expected primary allocated through **T, got %v", cfg.Primary)
+	}
+
+	expected := []string{"TEST_SERVERS_*_HOST", "TEST_SERVERS_*_PORT", "TEST_HOST", "TEST_PORT"}
+	if names := EnvVars(&cfg, WithEnvPrefix("TEST")); !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected EnvVars %v, got %v", expected, names)
+	}
+
+	var empty ChainConfig
+
+	err = Load(&empty,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if empty.Primary != nil {
+		t.Errorf("Expected primary to stay nil without env, got %v", empty.Primary)
+	}
+}
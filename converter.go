@@ -0,0 +1,48 @@
+package cfg
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	convertersMu sync.RWMutex
+	converters   = make(map[reflect.Type]func(string) (any, error))
+)
+
+// RegisterConverter registers a function that parses env, flag and override
+// values of type t. It is consulted before the built-in conversions, so it can
+// also replace them for a type such as bool. Registering nil removes the converter.
+func RegisterConverter(t reflect.Type, convert func(s string) (any, error)) {
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+
+	if convert == nil {
+		delete(converters, t)
+		return
+	}
+	converters[t] = convert
+}
+
+func lookupConverter(t reflect.Type) (func(string) (any, error), bool) {
+	convertersMu.RLock()
+	defer convertersMu.RUnlock()
+
+	convert, ok := converters[t]
+	return convert, ok
+}
+
+// convertWithConverter parses value with the registered converter for t.
+func convertWithConverter(t reflect.Type, value string, convert func(string) (any, error)) (reflect.Value, error) {
+	result, err := convert(value)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	converted := reflect.ValueOf(result)
+	if !converted.IsValid() || !converted.Type().AssignableTo(t) {
+		return reflect.Value{}, fmt.Errorf("converter for %s returned %T", t, result)
+	}
+	return converted, nil
+}
@@ -0,0 +1,101 @@
+package cfg
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testSeverity int
+
+type testPoint struct {
+	X, Y int
+}
+
+func TestRegisterConverter(t *testing.T) {
+	severities := map[string]testSeverity{"debug": 1, "info": 2}
+	RegisterConverter(reflect.TypeOf(testSeverity(0)), func(s string) (any, error) {
+		level, ok := severities[s]
+		if !ok {
+			return nil, fmt.Errorf("unknown level %q", s)
+		}
+		return level, nil
+	})
+	// Структура с конвертером задается одним значением, а не по полям
+	RegisterConverter(reflect.TypeOf(testPoint{}), func(s string) (any, error) {
+		var p testPoint
+		_, err := fmt.Sscanf(s, "%d:%d", &p.X, &p.Y)
+		return p, err
+	})
+	t.Cleanup(func() {
+		RegisterConverter(reflect.TypeOf(testSeverity(0)), nil)
+		RegisterConverter(reflect.TypeOf(testPoint{}), nil)
+	})
+
+	type Config struct {
+		Level  testSeverity   `yaml:"level" env:"LEVEL"`
+		Origin testPoint      `yaml:"origin" env:"ORIGIN"`
+		Levels []testSeverity `yaml:"levels" env:"LEVELS"`
+	}
+
+	var config Config
+	err := Load(&config,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{
+			"TEST_LEVEL":  "info",
+			"TEST_ORIGIN": "3:4",
+			"TEST_LEVELS": "debug,info",
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if config.Level != 2 {
+		t.Errorf("Expected level 2, got %d", config.Level)
+	}
+
+	if config.Origin != (testPoint{X: 3, Y: 4}) {
+		t.Errorf("Expected origin 3:4, got %+v", config.Origin)
+	}
+
+	if len(config.Levels) != 2 || config.Levels[0] != 1 || config.Levels[1] != 2 {
+		t.Errorf("Expected levels [1 2], got %v", config.Levels)
+	}
+
+	err = Load(&config,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_LEVEL": "trace"}),
+	)
+	if err == nil || !strings.Contains(err.Error(), `unknown level "trace"`) {
+		t.Errorf("Expected converter error, got %v", err)
+	}
+}
+
+func TestRegisterConverterWrongType(t *testing.T) {
+	type port uint16
+	RegisterConverter(reflect.TypeOf(port(0)), func(s string) (any, error) {
+		return s, nil
+	})
+	t.Cleanup(func() {
+		RegisterConverter(reflect.TypeOf(port(0)), nil)
+	})
+
+	var config struct {
+		Port port `yaml:"port" env:"PORT"`
+	}
+	err := Load(&config,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_PORT": "80"}),
+	)
+	if err == nil || !strings.Contains(err.Error(), "returned string") {
+		t.Errorf("Expected converter type error, got %v", err)
+	}
+}
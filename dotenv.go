@@ -0,0 +1,119 @@
+package cfg
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// loadDotEnv reads .env files into parameters. Missing files are skipped.
+func loadDotEnv(params *parameters) error {
+	if len(params.dotEnvPaths) == 0 {
+		return nil
+	}
+
+	params.dotEnv = make(map[string]string)
+	for _, path := range params.dotEnvPaths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return fmt.Errorf("unread file %s: %w", path, err)
+		}
+
+		if err := checkFileMode(path, params); err != nil {
+			return err
+		}
+
+		if err := parseDotEnv(data, params.dotEnv); err != nil {
+			return fmt.Errorf("unparse dotenv %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// parseDotEnv parses KEY=VALUE lines into env. Blank lines and lines starting
+// with # are skipped, an optional "export " prefix is allowed. Values may be
+// double quoted (with Go escapes), single quoted (literal) or unquoted, where
+// a " #" starts a trailing comment.
+func parseDotEnv(data []byte, env map[string]string) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return fmt.Errorf("line %d: expected KEY=VALUE", lineNumber)
+		}
+
+		value, err := parseDotEnvValue(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		env[key] = value
+	}
+
+	return scanner.Err()
+}
+
+// parseDotEnvValue unquotes a quoted value, which may be followed only by whitespace
+// and a # comment. Unquoted values end at a " #" comment.
+func parseDotEnvValue(value string) (string, error) {
+	if value != "" && (value[0] == '"' || value[0] == '\'') {
+		end := closingQuote(value)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated quoted value %s", value)
+		}
+
+		if rest := strings.TrimSpace(value[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", fmt.Errorf("unexpected %q after quoted value", rest)
+		}
+
+		if value[0] == '\'' {
+			return value[1:end], nil
+		}
+
+		unquoted, err := strconv.Unquote(value[:end+1])
+		if err != nil {
+			return "", fmt.Errorf("invalid quoted value %s", value[:end+1])
+		}
+		return unquoted, nil
+	}
+
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+
+	return value, nil
+}
+
+// closingQuote returns the index of the quote closing value[0], skipping escaped
+// quotes in double quoted values, or -1 when there is none.
+func closingQuote(value string) int {
+	for i := 1; i < len(value); i++ {
+		switch {
+		case value[0] == '"' && value[i] == '\\':
+			i++
+		case value[i] == value[0]:
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,95 @@
+package cfg
+
+import (
+	"testing"
+)
+
+func TestParseDotEnv(t *testing.T) {
+	env := make(map[string]string)
+
+	err := parseDotEnv([]byte(`
+# comment
+PLAIN=value
+export EXPORTED=exported
+DOUBLE="line\nbreak"
+SINGLE='keep \n as is'
+COMMENTED=value # trailing comment
+QUOTED_COMMENT="hello world" # trailing comment
+SINGLE_COMMENT='hello world'   # trailing comment
+HASH_IN_QUOTES="color #fff"
+HASH_IN_SINGLE='a # b'
+ESCAPED_QUOTE="say \"hi\"" # comment
+EMPTY=
+`), env)
+
+	if err != nil {
+		t.Fatalf("parseDotEnv failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"PLAIN":     "value",
+		"EXPORTED":  "exported",
+		"DOUBLE":    "line\nbreak",
+		"SINGLE":    `keep \n as is`,
+		"COMMENTED": "value",
+		// кавычки снимаются до отбрасывания комментария, # внутри кавычек сохраняется
+		"QUOTED_COMMENT": "hello world",
+		"SINGLE_COMMENT": "hello world",
+		"HASH_IN_QUOTES": "color #fff",
+		"HASH_IN_SINGLE": "a # b",
+		"ESCAPED_QUOTE":  `say "hi"`,
+		"EMPTY":          "",
+	}
+
+	for key, value := range expected {
+		if env[key] != value {
+			t.Errorf("Expected %s=%q, got %q", key, value, env[key])
+		}
+	}
+
+	if err := parseDotEnv([]byte("NOT A PAIR"), env); err == nil {
+		t.Error("Expected error for line without '='")
+	}
+
+	for _, line := range []string{`UNTERMINATED="value`, `TRAILING="value" extra`} {
+		if err := parseDotEnv([]byte(line), env); err == nil {
+			t.Errorf("Expected error for %s", line)
+		}
+	}
+}
+
+func TestLoadWithDotEnv(t *testing.T) {
+	t.Parallel()
+
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("config"),
+		WithEnvPrefix("TEST"),
+		WithDotEnv("./test/.env"),
+		WithEnv(map[string]string{"TEST_SERVER_PORT": "9090"}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// .env переопределяет YAML
+	if cfg.Database.Host != "dotenv.db.localhost" {
+		t.Errorf("Expected database.host 'dotenv.db.localhost' from .env, got '%s'", cfg.Database.Host)
+	}
+
+	if cfg.Database.Name != "dotenv_db" {
+		t.Errorf("Expected database.name 'dotenv_db' from .env, got '%s'", cfg.Database.Name)
+	}
+
+	if cfg.App.Name != "dotenv app" {
+		t.Errorf("Expected app.name 'dotenv app' from .env, got '%s'", cfg.App.Name)
+	}
+
+	// Окружение процесса приоритетнее .env
+	if cfg.Server.Port != 9090 {
+		t.Errorf("Expected server.port 9090 from env, got %d", cfg.Server.Port)
+	}
+}
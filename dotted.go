@@ -0,0 +1,74 @@
+package cfg
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// expandDottedKeys rewrites top-level keys that are dotted paths (server.port: 9090)
+// into nested mappings. Nested keys are kept as is, so map keys like
+// app.kubernetes.io/name below the top level are not split.
+func expandDottedKeys(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return data, nil
+	}
+
+	root := doc.Content[0]
+	expanded := &yaml.Node{Kind: yaml.MappingNode}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if err := setDottedKey(expanded, strings.Split(key.Value, "."), value); err != nil {
+			return nil, fmt.Errorf("line %d: key %s: %w", key.Line, key.Value, err)
+		}
+	}
+
+	doc.Content[0] = expanded
+	return yaml.Marshal(&doc)
+}
+
+// setDottedKey sets value at path in mapping. Mappings set at the same path are
+// merged key by key, any other value replaces the previous one.
+func setDottedKey(mapping *yaml.Node, path []string, value *yaml.Node) error {
+	var existing *yaml.Node
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == path[0] {
+			existing = mapping.Content[i+1]
+			if len(path) == 1 && (existing.Kind != yaml.MappingNode || value.Kind != yaml.MappingNode) {
+				mapping.Content[i+1] = value
+				return nil
+			}
+			break
+		}
+	}
+
+	if existing == nil {
+		if len(path) == 1 {
+			mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: path[0]}, value)
+			return nil
+		}
+		existing = &yaml.Node{Kind: yaml.MappingNode}
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: path[0]}, existing)
+	}
+
+	if existing.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s is already set to a value", path[0])
+	}
+
+	if len(path) > 1 {
+		return setDottedKey(existing, path[1:], value)
+	}
+
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		if err := setDottedKey(existing, []string{value.Content[i].Value}, value.Content[i+1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
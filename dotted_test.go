@@ -0,0 +1,64 @@
+package cfg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDottedKeys(t *testing.T) {
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("dotted_config"),
+		WithDottedKeys(),
+		WithEnv(map[string]string{}),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.App.Name != "dotted-app" || cfg.Database.Host != "db.dotted" {
+		t.Errorf("Expected values from dotted keys, got %+v %+v", cfg.App, cfg.Database)
+	}
+
+	// Плоские и вложенные ключи одной секции объединяются
+	if cfg.Server.Port != 9090 || cfg.Server.Host != "dotted.localhost" || !cfg.Server.Debug {
+		t.Errorf("Expected merged server section, got %+v", cfg.Server)
+	}
+
+	var plain TestConfig
+	err = Load(&plain,
+		WithPaths("./test"),
+		WithName("dotted_config"),
+		WithEnv(map[string]string{}),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if plain.Server.Port != 0 {
+		t.Errorf("Expected dotted keys to be ignored without WithDottedKeys, got port %d", plain.Server.Port)
+	}
+}
+
+func TestDottedKeysKeepNestedKeys(t *testing.T) {
+	type Config struct {
+		Labels map[string]string `yaml:"labels"`
+	}
+
+	var cfg Config
+	err := LoadReader(&cfg, strings.NewReader("labels:\n  app.kubernetes.io/name: web\n"), WithDottedKeys())
+	if err != nil {
+		t.Fatalf("LoadReader failed: %v", err)
+	}
+
+	if cfg.Labels["app.kubernetes.io/name"] != "web" {
+		t.Errorf("Expected nested dotted map key to be kept, got %v", cfg.Labels)
+	}
+
+	err = LoadReader(&cfg, strings.NewReader("server: 1\nserver.port: 2\n"), WithDottedKeys())
+	if err == nil || !strings.Contains(err.Error(), "line 2: key server.port: server is already set to a value") {
+		t.Errorf("Expected conflict error, got %v", err)
+	}
+}
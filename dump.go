@@ -0,0 +1,117 @@
+package cfg
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"reflect"
+)
+
+// Dump marshals the effective configuration to YAML.
+func Dump(cfg any) ([]byte, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("marshal config: %w", err)
+	}
+	return data, nil
+}
+
+// DumpTo writes the effective configuration as YAML to w.
+func DumpTo(w io.Writer, cfg any) error {
+	data, err := Dump(cfg)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	return nil
+}
+
+// redactedValue replaces string fields tagged secret:"true" in dumps.
+const redactedValue = "***"
+
+// DumpRedacted marshals the effective configuration to YAML with fields tagged
+// secret:"true" masked. Secret strings are replaced with "***", secret fields of
+// other types are zeroed. cfg itself is not modified.
+func DumpRedacted(cfg any) ([]byte, error) {
+	if cfg == nil {
+		return Dump(cfg)
+	}
+	return Dump(redact(reflect.ValueOf(cfg)).Interface())
+}
+
+// redact returns a deep copy of v with secret fields masked.
+func redact(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		ptr := reflect.New(v.Type().Elem())
+		ptr.Elem().Set(redact(v.Elem()))
+		return ptr
+	case reflect.Struct:
+		// time.Time, url.URL и другие значения копируются целиком
+		if isValueType(v.Type()) {
+			return v
+		}
+
+		// копия сохраняет неэкспортируемые поля, экспортируемые обходим ниже
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			field := copied.Field(i)
+			if !field.CanSet() {
+				continue
+			}
+
+			if v.Type().Field(i).Tag.Get("secret") == "true" {
+				if field.Kind() == reflect.String {
+					field.SetString(redactedValue)
+				} else {
+					field.SetZero()
+				}
+				continue
+			}
+
+			field.Set(redact(v.Field(i)))
+		}
+		return copied
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(redact(v.Index(i)))
+		}
+		return copied
+	case reflect.Array:
+		copied := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(redact(v.Index(i)))
+		}
+		return copied
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), redact(iter.Value()))
+		}
+		return copied
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(redact(v.Elem()))
+		return copied
+	default:
+		return v
+	}
+}
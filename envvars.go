@@ -0,0 +1,207 @@
+package cfg
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// envVarField is an env variable name and the path of the field it overrides,
+// with the deprecated names from its envAlias tag.
+type envVarField struct {
+	name    string
+	path    string
+	aliases []string
+}
+
+// EnvVars returns the names of all env variables that can override a field of cfg,
+// with the prefix applied, in field order. Map fields are listed as <NAME>_*, fields
+// of slice elements as <NAME>_*_<FIELD>.
+// It honors the same options as Load, e.g. WithEnvPrefix or WithAutoEnv.
+func EnvVars(cfg any, paramsActions ...Action) []string {
+	fields, err := envVarFields(cfg, paramsActions)
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.name
+	}
+	return names
+}
+
+// CheckEnvVars reports env variables shared by several fields, where one would
+// silently shadow the other. Each collision names both field paths.
+func CheckEnvVars(cfg any, paramsActions ...Action) error {
+	fields, err := envVarFields(cfg, paramsActions)
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(envVarCollisions(fields)...)
+}
+
+func envVarCollisions(fields []envVarField) []error {
+	var errs []error
+	seen := make(map[string]string)
+	for _, field := range fields {
+		if other, ok := seen[field.name]; ok {
+			errs = append(errs, fmt.Errorf("env %s is used by both %s and %s", field.name, other, field.path))
+			continue
+		}
+		seen[field.name] = field.path
+	}
+	return errs
+}
+
+// logEnvUsage reports to WithDebugLog the env names shared by several fields and
+// the variables with the env prefix that no field consumed, e.g. typos.
+func logEnvUsage(cfg any, params *parameters) {
+	for _, err := range envVarCollisions(collectEnvVars(reflect.TypeOf(cfg).Elem(), params, "", "", nil)) {
+		params.debugf("%v", err)
+	}
+
+	if params.envPrefix == "" {
+		return
+	}
+
+	prefix := params.envPrefix + params.prefixSeparator
+	var ignored []string
+	for key := range params.environ() {
+		if strings.HasPrefix(envKey(key, params), envKey(prefix, params)) && !params.consumedEnv[envKey(key, params)] {
+			ignored = append(ignored, key)
+		}
+	}
+
+	sort.Strings(ignored)
+	for _, key := range ignored {
+		params.debugf("env %s: ignored, no field uses it", key)
+	}
+}
+
+func envVarFields(cfg any, paramsActions []Action) ([]envVarField, error) {
+	if err := validateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	p, err := newParameters(paramsActions)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectEnvVars(reflect.TypeOf(cfg).Elem(), p, "", "", nil), nil
+}
+
+func collectEnvVars(t reflect.Type, params *parameters, path, envPath string, fields []envVarField) []envVarField {
+	defer params.enterWalk(t)()
+
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		fieldType := structField.Type
+
+		if structField.Anonymous && fieldType.Kind() == reflect.Struct {
+			fields = collectEnvVars(fieldType, params, path, envPath, fields)
+			continue
+		}
+
+		if !structField.IsExported() || structField.Tag.Get(params.envTag) == "-" {
+			continue
+		}
+
+		fieldPath := joinFieldPath(path, structField.Name)
+		fieldEnvPath := joinFieldPath(envPath, envPathSegment(structField, params))
+
+		if isStructPtrChain(fieldType) {
+			// рекурсивный тип (Fallback *Node) уже перечислен выше по пути
+			if params.onWalkPath(fieldType) {
+				continue
+			}
+			for fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+		}
+
+		if fieldType.Kind() == reflect.Struct && !isValueType(fieldType) {
+			fields = collectEnvVars(fieldType, params, fieldPath, fieldEnvPath, fields)
+			continue
+		}
+
+		envVar := getEnvVarName(structField, params, fieldEnvPath)
+		if envVar == "" {
+			continue
+		}
+
+		if (fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array) &&
+			(isStructPtrChain(fieldType.Elem()) || fieldType.Elem().Kind() == reflect.Struct && !isValueType(fieldType.Elem())) {
+			elemType := fieldType.Elem()
+			for elemType.Kind() == reflect.Ptr {
+				elemType = elemType.Elem()
+			}
+			if params.onWalkPath(elemType) {
+				continue
+			}
+			elemParams := *params
+			elemParams.envPrefix = envVar + params.prefixSeparator + "*"
+			fields = collectEnvVars(elemType, &elemParams, fieldPath+"[*]", "", fields)
+			continue
+		}
+
+		if fieldType.Kind() == reflect.Map {
+			envVar += params.prefixSeparator + "*"
+		}
+		fields = append(fields, envVarField{name: envVar, path: fieldPath, aliases: envAliasNames(structField, params)})
+	}
+
+	return fields
+}
+
+// checkStrictEnv reports variables with the env prefix that match no field env name
+// (including _FILE variants and map / slice patterns) and are not allowed explicitly.
+func checkStrictEnv(cfg any, params *parameters) error {
+	if params.envPrefix == "" {
+		return nil
+	}
+
+	known := collectEnvVars(reflect.TypeOf(cfg).Elem(), params, "", "", nil)
+	allowed := make(map[string]bool)
+	for _, names := range [][]string{params.strictEnvAllow, {params.configPathEnv, params.nameEnv, params.pathsEnv}} {
+		for _, name := range names {
+			allowed[envKey(name, params)] = true
+		}
+	}
+
+	prefix := envKey(params.envPrefix+params.prefixSeparator, params)
+	var errs []error
+	for key := range params.environ() {
+		name := envKey(key, params)
+		if !strings.HasPrefix(name, prefix) || allowed[name] || isKnownEnvVar(name, known, params) {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("unknown env %s", key))
+	}
+
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errors.Join(errs...)
+}
+
+func isKnownEnvVar(name string, known []envVarField, params *parameters) bool {
+	base, _ := strings.CutSuffix(name, "_FILE")
+	for _, field := range known {
+		for _, pattern := range append([]string{field.name}, field.aliases...) {
+			pattern = envKey(pattern, params)
+			for _, candidate := range []string{name, base} {
+				if pattern == candidate {
+					return true
+				}
+				if ok, _ := path.Match(pattern, candidate); ok && strings.Contains(pattern, "*") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,257 @@
+package cfg
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestEnvVars(t *testing.T) {
+	type Config struct {
+		TestCommon `yaml:",inline"`
+		Server     *TestServer       `yaml:"server"`
+		Labels     map[string]string `yaml:"labels" env:"LABELS"`
+		Timeout    int               `yaml:"timeout"`
+		Ignored    string            `yaml:"ignored" env:"-"`
+		Upstreams  []TestDatabase    `yaml:"upstreams" env:"UPSTREAMS"`
+	}
+
+	names := EnvVars(&Config{}, WithEnvPrefix("MYAPP"))
+
+	expected := []string{
+		"MYAPP_NAME",
+		"MYAPP_VERSION",
+		"MYAPP_SERVER_HOST",
+		"MYAPP_SERVER_PORT",
+		"MYAPP_SERVER_DEBUG",
+		"MYAPP_LABELS_*",
+		"MYAPP_UPSTREAMS_*_DB_HOST",
+		"MYAPP_UPSTREAMS_*_DB_PORT",
+		"MYAPP_UPSTREAMS_*_DB_NAME",
+	}
+
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+
+	auto := EnvVars(&Config{}, WithEnvPrefix("MYAPP"), WithAutoEnv())
+	if !slices.Contains(auto, "MYAPP_TIMEOUT") {
+		t.Errorf("Expected MYAPP_TIMEOUT with auto env, got %v", auto)
+	}
+
+	if names := EnvVars(Config{}); names != nil {
+		t.Errorf("Expected nil for non-pointer config, got %v", names)
+	}
+}
+
+func TestCheckEnvVars(t *testing.T) {
+	type Config struct {
+		Server   TestServer   `yaml:"server"`
+		Database TestDatabase `yaml:"database"`
+		Backup   struct {
+			Host string `yaml:"host" env:"DB_HOST"`
+		} `yaml:"backup"`
+	}
+
+	err := CheckEnvVars(&Config{}, WithEnvPrefix("MYAPP"))
+	if err == nil {
+		t.Fatal("Expected error for duplicate env tag")
+	}
+
+	expected := "env MYAPP_DB_HOST is used by both Database.Host and Backup.Host"
+	if err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, err.Error())
+	}
+
+	if err := CheckEnvVars(&TestConfig{}); err != nil {
+		t.Errorf("Expected no collisions in TestConfig, got %v", err)
+	}
+}
+
+func TestDebugLog(t *testing.T) {
+	type Config struct {
+		Server TestServer        `yaml:"server"`
+		Host   string            `yaml:"host" env:"SERVER_HOST"`
+		Labels map[string]string `yaml:"labels" env:"LABELS"`
+	}
+
+	var logs []string
+	var config Config
+	err := Load(&config,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{
+			"TEST_SERVER_PORT": "9090",
+			"TEST_LABELS_TEAM": "core",
+			"TEST_SERVR_DEBUG": "true",
+			"OTHER_THING":      "1",
+		}),
+		WithDebugLog(func(msg string) {
+			logs = append(logs, msg)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	joined := strings.Join(logs, "\n")
+	for _, expected := range []string{
+		"env TEST_SERVER_PORT: set",
+		"env TEST_SERVER_DEBUG: not set",
+		"env TEST_LABELS_TEAM: set map key team",
+		"env TEST_SERVR_DEBUG: ignored, no field uses it",
+		"env TEST_SERVER_HOST is used by both Server.Host and Host",
+	} {
+		if !strings.Contains(joined, expected) {
+			t.Errorf("Expected debug log to contain %q, got:\n%s", expected, joined)
+		}
+	}
+
+	// Значения не попадают в лог, посторонние переменные не упоминаются
+	if strings.Contains(joined, "9090") || strings.Contains(joined, "OTHER_THING") {
+		t.Errorf("Expected no values and no foreign variables in debug log, got:\n%s", joined)
+	}
+}
+
+func TestStrictEnv(t *testing.T) {
+	type Config struct {
+		Server TestServer        `yaml:"server"`
+		Labels map[string]string `yaml:"labels" env:"LABELS"`
+		Token  string            `yaml:"token" env:"TOKEN"`
+	}
+
+	env := map[string]string{
+		"TEST_SERVER_PORT":  "9090",
+		"TEST_LABELS_TEAM":  "core",
+		"TEST_TOKEN_FILE":   "./test/db_password.txt",
+		"TEST_CONFIG_FILE":  "",
+		"TEST_SERVER_PROT":  "9091",
+		"TEST_SERVER_DEBGU": "true",
+		"TEST_EXTRA":        "allowed",
+		"OTHER_SERVER_PROT": "1",
+	}
+
+	var config Config
+	err := Load(&config,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(env),
+		WithConfigPathEnv("TEST_CONFIG_FILE"),
+		WithStrictEnv("TEST_EXTRA"),
+	)
+
+	expected := "load env: unknown env TEST_SERVER_DEBGU\nunknown env TEST_SERVER_PROT"
+	if err == nil || !strings.HasSuffix(err.Error(), expected) {
+		t.Errorf("Expected error %q, got %v", expected, err)
+	}
+
+	delete(env, "TEST_SERVER_PROT")
+	delete(env, "TEST_SERVER_DEBGU")
+
+	// Известные имена, _FILE, ключи map и разрешенные переменные не считаются ошибкой
+	err = Load(&config,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(env),
+		WithConfigPathEnv("TEST_CONFIG_FILE"),
+		WithStrictEnv("TEST_EXTRA"),
+	)
+	if err != nil {
+		t.Errorf("Load failed: %v", err)
+	}
+}
+
+func TestEnvAlias(t *testing.T) {
+	type Config struct {
+		Server struct {
+			Port int    `yaml:"port" env:"SERVER_PORT" envAlias:"PORT,LISTEN_PORT"`
+			Host string `yaml:"host" env:"SERVER_HOST" envAlias:"HOST"`
+		} `yaml:"server"`
+	}
+
+	var deprecated []string
+	deprecationLog := WithDeprecationLog(func(oldName, newName string) {
+		deprecated = append(deprecated, oldName+" -> "+newName)
+	})
+
+	var config Config
+	err := Load(&config,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{
+			"TEST_LISTEN_PORT": "9090",
+			"TEST_SERVER_HOST": "new.example.com",
+			"TEST_HOST":        "old.example.com",
+		}),
+		WithStrictEnv(),
+		deprecationLog,
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Старое имя используется, только если новое не задано
+	if config.Server.Port != 9090 {
+		t.Errorf("Expected server.port 9090 from alias, got %d", config.Server.Port)
+	}
+	if config.Server.Host != "new.example.com" {
+		t.Errorf("Expected primary name to win, got host %q", config.Server.Host)
+	}
+
+	expected := "TEST_LISTEN_PORT -> TEST_SERVER_PORT"
+	if len(deprecated) != 1 || deprecated[0] != expected {
+		t.Errorf("Expected deprecation %q, got %v", expected, deprecated)
+	}
+}
+
+func TestStrictEnvPathsFromEnv(t *testing.T) {
+	var config TestConfig
+	err := Load(&config,
+		WithName("config"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_CONFIG_PATHS": "./test"}),
+		WithPathsFromEnv("TEST_CONFIG_PATHS"),
+		WithStrictEnv(),
+	)
+	if err != nil {
+		t.Fatalf("Expected the WithPathsFromEnv variable to be exempt, got %v", err)
+	}
+
+	if config.App.Name != "test-app" {
+		t.Errorf("Expected app.name test-app from ./test, got %q", config.App.Name)
+	}
+}
+
+func TestEnvVarsRecursiveType(t *testing.T) {
+	// Обход останавливается на типе, который уже есть на пути
+	names := EnvVars(&testNode{})
+	if !reflect.DeepEqual(names, []string{"APP_NAME"}) {
+		t.Errorf("Expected [APP_NAME], got %v", names)
+	}
+
+	if err := CheckEnvVars(&testNode{}); err != nil {
+		t.Errorf("Expected no collisions, got %v", err)
+	}
+
+	var logged []string
+	var config testNode
+	err := Load(&config,
+		WithoutFile(),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_NAME": "node"}),
+		WithStrictEnv(),
+		WithDebugLog(func(msg string) { logged = append(logged, msg) }),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if config.Name != "node" || len(logged) == 0 {
+		t.Errorf("Expected name node and debug output, got %+v, log %v", config, logged)
+	}
+}
@@ -0,0 +1,60 @@
+package cfg
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNilConfig is returned when the config or the pointer to it is nil.
+	ErrNilConfig = errors.New("config must not be nil")
+	// ErrNotPointer is returned when the config is not a pointer to struct.
+	ErrNotPointer = errors.New("config must be a pointer to struct")
+	// ErrConfigNotFound is returned by WithRequireFile when no config file was found.
+	ErrConfigNotFound = errors.New("config not found")
+	// ErrRequired is wrapped by errors for required fields left empty.
+	ErrRequired = errors.New("required")
+	// ErrInvalidOption is returned when an option has an invalid argument.
+	ErrInvalidOption = errors.New("invalid option")
+	// ErrEmptyFile is returned by WithErrorOnEmptyFile for a config file without content.
+	ErrEmptyFile = errors.New("config file is empty")
+	// ErrFileMode is returned by WithMaxFileMode for a config file with too open permissions.
+	ErrFileMode = errors.New("config file permissions are too open")
+)
+
+// ParseError reports a config file or reader that cannot be decoded.
+type ParseError struct {
+	// File is the path of the file, empty for LoadReader.
+	File string
+	// Format is the format name, e.g. "yaml".
+	Format string
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("unparse %s reader: %v", e.Format, e.Err)
+	}
+	return fmt.Sprintf("unparse %s %s: %v", e.Format, e.File, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// EnvError reports an environment variable that cannot be assigned to a field.
+type EnvError struct {
+	// Field is the Go path of the field, e.g. "Server.Port".
+	Field string
+	// Var is the variable name, for map fields the prefix followed by "_*".
+	Var string
+	Err error
+}
+
+func (e *EnvError) Error() string {
+	return fmt.Sprintf("field %s: env %s: %v", e.Field, e.Var, e.Err)
+}
+
+func (e *EnvError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,275 @@
+package cfg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestErrInvalidConfig(t *testing.T) {
+	var nilConfig *TestConfig
+
+	tests := []struct {
+		name     string
+		cfg      any
+		expected error
+	}{
+		{"nil", nil, ErrNilConfig},
+		{"nil pointer", nilConfig, ErrNilConfig},
+		{"struct", TestConfig{}, ErrNotPointer},
+		{"pointer to non-struct", new(int), ErrNotPointer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Load(tt.cfg, WithPaths("./nonexistent"))
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestErrConfigNotFound(t *testing.T) {
+	var cfg TestConfig
+
+	err := Load(&cfg, WithPaths("./nonexistent"), WithRequireFile())
+	if !errors.Is(err, ErrConfigNotFound) {
+		t.Errorf("Expected ErrConfigNotFound, got %v", err)
+	}
+}
+
+func TestErrEmptyFile(t *testing.T) {
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("empty_config"),
+		WithEnv(map[string]string{}),
+		WithErrorOnEmptyFile(),
+	)
+	if !errors.Is(err, ErrEmptyFile) || !strings.Contains(err.Error(), "empty_config.yaml") {
+		t.Errorf("Expected ErrEmptyFile naming the file, got %v", err)
+	}
+
+	// По умолчанию пустой файл ничего не меняет
+	err = Load(&cfg,
+		WithPaths("./test"),
+		WithName("empty_config"),
+		WithEnv(map[string]string{}),
+	)
+	if err != nil {
+		t.Errorf("Expected empty file to be accepted by default, got %v", err)
+	}
+}
+
+func TestParseErrorType(t *testing.T) {
+	var cfg TestConfig
+
+	err := LoadReader(&cfg, strings.NewReader("server: [broken\n"))
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("Expected ParseError, got %v", err)
+	}
+
+	if parseErr.Format != "yaml" || parseErr.File != "" {
+		t.Errorf("Expected yaml reader error, got format %q file %q", parseErr.Format, parseErr.File)
+	}
+}
+
+func TestEnvErrorType(t *testing.T) {
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithPaths("./nonexistent"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_SERVER_PORT": "abc"}),
+	)
+
+	var envErr *EnvError
+	if !errors.As(err, &envErr) {
+		t.Fatalf("Expected EnvError, got %v", err)
+	}
+
+	if envErr.Field != "Server.Port" || envErr.Var != "TEST_SERVER_PORT" {
+		t.Errorf("Expected Server.Port / TEST_SERVER_PORT, got %s / %s", envErr.Field, envErr.Var)
+	}
+}
+
+func TestErrRequired(t *testing.T) {
+	type Config struct {
+		Name string `yaml:"name" required:"true"`
+	}
+
+	var cfg Config
+	err := Load(&cfg, WithPaths("./nonexistent"))
+	if !errors.Is(err, ErrRequired) {
+		t.Errorf("Expected ErrRequired, got %v", err)
+	}
+}
+
+func TestErrInvalidOption(t *testing.T) {
+	for _, prefix := range []string{"MY APP", "APP=1", "my-app"} {
+		var cfg TestConfig
+
+		err := Load(&cfg, WithPaths("./nonexistent"), WithEnvPrefix(prefix))
+		if !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("Expected ErrInvalidOption for prefix %q, got %v", prefix, err)
+		}
+	}
+
+	for _, prefix := range []string{"", "app", "MY_APP_", "APP2"} {
+		var cfg TestConfig
+
+		if err := Load(&cfg, WithPaths("./nonexistent"), WithEnvPrefix(prefix), WithEnv(map[string]string{})); err != nil {
+			t.Errorf("Expected prefix %q to be valid, got %v", prefix, err)
+		}
+	}
+
+	if err := CheckEnvVars(&TestConfig{}, WithEnvPrefix("MY APP")); !errors.Is(err, ErrInvalidOption) {
+		t.Errorf("Expected ErrInvalidOption from CheckEnvVars, got %v", err)
+	}
+}
+
+func TestFromOption(t *testing.T) {
+	var cfg TestConfig
+
+	failing := FromOption(func(*parameters) error {
+		return errors.New("boom")
+	})
+
+	err := Load(&cfg, WithPaths("./nonexistent"), failing, WithFormat("xml"))
+	if !errors.Is(err, ErrInvalidOption) {
+		t.Fatalf("Expected ErrInvalidOption, got %v", err)
+	}
+
+	// Ошибки всех опций собираются вместе
+	for _, expected := range []string{"boom", `unsupported format "xml"`} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected error to contain '%s', got: %v", expected, err)
+		}
+	}
+
+	applied := FromOption(func(p *parameters) error {
+		p.names = []string{"config"}
+		return nil
+	})
+
+	if err := Load(&cfg, WithPaths("./test"), applied, WithEnv(map[string]string{})); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.App.Name != "test-app" {
+		t.Errorf("Expected option to be applied, got app.name '%s'", cfg.App.Name)
+	}
+}
+
+func TestErrFileMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not checked on windows")
+	}
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(file, []byte("app:\n  name: secret-app\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if err := os.Chmod(file, 0o644); err != nil {
+		t.Fatalf("Failed to chmod config: %v", err)
+	}
+
+	var cfg TestConfig
+	err := Load(&cfg,
+		WithPaths(dir),
+		WithEnv(map[string]string{}),
+		WithMaxFileMode(0o600),
+	)
+	if !errors.Is(err, ErrFileMode) || !strings.Contains(err.Error(), file) || !strings.Contains(err.Error(), "0644") {
+		t.Errorf("Expected ErrFileMode naming the file and its mode, got %v", err)
+	}
+
+	// Более строгие права допустимы
+	if err := os.Chmod(file, 0o400); err != nil {
+		t.Fatalf("Failed to chmod config: %v", err)
+	}
+
+	err = Load(&cfg,
+		WithPaths(dir),
+		WithEnv(map[string]string{}),
+		WithMaxFileMode(0o600),
+	)
+	if err != nil {
+		t.Fatalf("Expected mode 0400 to be accepted, got %v", err)
+	}
+	if cfg.App.Name != "secret-app" {
+		t.Errorf("Expected app.name secret-app, got %q", cfg.App.Name)
+	}
+}
+
+func TestErrFileModeIncludesAndSecrets(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not checked on windows")
+	}
+
+	dir := t.TempDir()
+	writeFile := func(name, content string, mode os.FileMode) string {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		if err := os.Chmod(file, mode); err != nil {
+			t.Fatalf("Failed to chmod %s: %v", name, err)
+		}
+		return file
+	}
+
+	writeFile("config.yaml", "database: !include database.yaml\n", 0o600)
+	include := writeFile("database.yaml", "name: secret-db\n", 0o644)
+
+	// Подключаемый файл проверяется так же, как основной
+	var cfg TestConfig
+	err := Load(&cfg,
+		WithPaths(dir),
+		WithEnv(map[string]string{}),
+		WithMaxFileMode(0o600),
+	)
+	if !errors.Is(err, ErrFileMode) || !strings.Contains(err.Error(), include) {
+		t.Errorf("Expected ErrFileMode naming the include, got %v", err)
+	}
+
+	if err := os.Chmod(include, 0o600); err != nil {
+		t.Fatalf("Failed to chmod include: %v", err)
+	}
+
+	secret := writeFile("db_name.txt", "secret-db\n", 0o644)
+	err = Load(&cfg,
+		WithPaths(dir),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_DB_NAME_FILE": secret}),
+		WithMaxFileMode(0o600),
+	)
+	if !errors.Is(err, ErrFileMode) || !strings.Contains(err.Error(), secret) {
+		t.Errorf("Expected ErrFileMode naming the _FILE target, got %v", err)
+	}
+
+	if err := os.Chmod(secret, 0o400); err != nil {
+		t.Fatalf("Failed to chmod secret: %v", err)
+	}
+
+	err = Load(&cfg,
+		WithPaths(dir),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_DB_NAME_FILE": secret}),
+		WithMaxFileMode(0o600),
+	)
+	if err != nil {
+		t.Fatalf("Expected strict modes to be accepted, got %v", err)
+	}
+	if cfg.Database.Name != "secret-db" {
+		t.Errorf("Expected database.name secret-db, got %q", cfg.Database.Name)
+	}
+}
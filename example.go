@@ -0,0 +1,118 @@
+package cfg
+
+import (
+	"bytes"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"reflect"
+)
+
+// Example returns a starter YAML config for the type of cfg: a zero value with
+// default tags applied and nested struct pointers allocated. Fields tagged
+// comment:"..." get the text as a comment above their key. cfg itself is not read.
+func Example(cfg any) ([]byte, error) {
+	if err := validateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	v := reflect.New(reflect.TypeOf(cfg).Elem())
+	if err := exampleDefaults(v.Elem(), defaultParameters()); err != nil {
+		return nil, fmt.Errorf("load defaults: %w", err)
+	}
+
+	var node yaml.Node
+	if err := node.Encode(v.Interface()); err != nil {
+		return nil, fmt.Errorf("marshal config: %w", err)
+	}
+	addComments(&node, v.Elem().Type())
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, fmt.Errorf("marshal config: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("marshal config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// exampleDefaults applies default tags to v, allocating nil pointers to structs
+// so they show up in the example with their own defaults. A pointer to a type
+// already on the current path (Fallback *Node inside Node) stays nil.
+func exampleDefaults(v reflect.Value, params *parameters) error {
+	defer params.enterWalk(v.Type())()
+
+	if err := loadStructDefaults(v, params); err != nil {
+		return err
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if !field.CanSet() && !v.Type().Field(i).Anonymous {
+			continue
+		}
+
+		switch {
+		case isNestedStruct(field):
+			if err := exampleDefaults(field, params); err != nil {
+				return err
+			}
+		case isStructPtr(field) && field.CanSet() && !params.onWalkPath(field.Type()):
+			field.Set(reflect.New(field.Type().Elem()))
+			if err := exampleDefaults(field.Elem(), params); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// addComments sets comment tags of t as head comments on the keys of node.
+func addComments(node *yaml.Node, t reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if node.Kind == yaml.DocumentNode {
+		for _, child := range node.Content {
+			addComments(child, t)
+		}
+		return
+	}
+
+	if node.Kind != yaml.MappingNode || t.Kind() != reflect.Struct {
+		return
+	}
+
+	fields := make(map[string]reflect.StructField)
+	collectYamlFields(t, fields)
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		field, ok := fields[node.Content[i].Value]
+		if !ok {
+			continue
+		}
+		if comment := field.Tag.Get("comment"); comment != "" {
+			node.Content[i].HeadComment = comment
+		}
+		addComments(node.Content[i+1], field.Type)
+	}
+}
+
+// collectYamlFields indexes fields of t by their yaml key, including fields of
+// structs inlined with yaml:",inline".
+func collectYamlFields(t reflect.Type, fields map[string]reflect.StructField) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if isInlined(field) {
+			collectYamlFields(field.Type, fields)
+			continue
+		}
+
+		fields[fieldKey(field)] = field
+	}
+}
@@ -0,0 +1,58 @@
+package cfg
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExample(t *testing.T) {
+	type Database struct {
+		Host string `yaml:"host" default:"localhost" comment:"Database host name"`
+		Port int    `yaml:"port" default:"5432"`
+	}
+	type Config struct {
+		TestCommon `yaml:",inline"`
+		Timeout    time.Duration `yaml:"timeout" default:"30s" comment:"Request timeout"`
+		Database   *Database     `yaml:"database" comment:"Primary database"`
+	}
+
+	data, err := Example(&Config{Timeout: time.Minute})
+	if err != nil {
+		t.Fatalf("Example failed: %v", err)
+	}
+
+	out := string(data)
+	for _, expected := range []string{
+		"name: \"\"",
+		"# Request timeout\ntimeout: 30s",
+		"# Primary database\ndatabase:\n",
+		"  # Database host name\n  host: localhost",
+		"  port: 5432",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected example to contain %q, got:\n%s", expected, out)
+		}
+	}
+
+	// Пример должен загружаться обратно без ошибок
+	var cfg Config
+	if err := LoadReader(&cfg, strings.NewReader(out), WithStrict(), WithEnv(map[string]string{})); err != nil {
+		t.Errorf("Expected example to load back, got %v", err)
+	}
+}
+
+func TestExampleRecursiveType(t *testing.T) {
+	t.Parallel()
+
+	data, err := Example(&testNode{})
+	if err != nil {
+		t.Fatalf("Example failed: %v", err)
+	}
+
+	// Указатель на тип, уже находящийся на пути, остается nil
+	expected := "name: \"\"\nfallback: null\n"
+	if string(data) != expected {
+		t.Errorf("Expected %q, got %q", expected, string(data))
+	}
+}
@@ -0,0 +1,84 @@
+package cfg
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Explain loads the configuration into cfg like Load and reports, per field
+// path (e.g. "Server.Port"), which stage set its final value: "default", "file",
+// "env", "flag", "override", "secret" or "defaulter". Fields no stage changed
+// are reported as "unset".
+func Explain(cfg any, paramsActions ...Action) (map[string]string, error) {
+	if err := validateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	sources := make(map[string]string)
+	previous := snapshotFields(reflect.ValueOf(cfg).Elem(), "", make(map[string]string))
+	for path := range previous {
+		sources[path] = "unset"
+	}
+
+	record := func(stage string) {
+		current := snapshotFields(reflect.ValueOf(cfg).Elem(), "", make(map[string]string))
+		for path, value := range current {
+			if old, ok := previous[path]; !ok || old != value {
+				sources[path] = stage
+			}
+		}
+		previous = current
+	}
+
+	actions := append(paramsActions[:len(paramsActions):len(paramsActions)], func(p *parameters) {
+		p.stageHook = record
+	})
+
+	if _, err := load(context.Background(), cfg, loadFromFile, actions); err != nil {
+		return nil, err
+	}
+
+	return sources, nil
+}
+
+// snapshotFields renders every leaf field of v, keyed by its path, so stages can
+// be compared without sharing slices or maps with the config.
+func snapshotFields(v reflect.Value, path string, values map[string]string) map[string]string {
+	t := v.Type()
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		structField := t.Field(i)
+
+		if structField.Anonymous && field.Kind() == reflect.Struct {
+			snapshotFields(field, path, values)
+			continue
+		}
+
+		if !field.CanSet() {
+			continue
+		}
+
+		fieldPath := joinFieldPath(path, structField.Name)
+
+		if isNestedStruct(field) {
+			snapshotFields(field, fieldPath, values)
+			continue
+		}
+
+		if isStructPtr(field) {
+			if !field.IsNil() {
+				snapshotFields(field.Elem(), fieldPath, values)
+			}
+			continue
+		}
+
+		if field.Kind() == reflect.Ptr && !field.IsNil() {
+			field = field.Elem()
+		}
+		values[fieldPath] = fmt.Sprintf("%#v", field.Interface())
+	}
+
+	return values
+}
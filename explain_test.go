@@ -0,0 +1,44 @@
+package cfg
+
+import "testing"
+
+func TestExplain(t *testing.T) {
+	type Config struct {
+		Server   TestServer   `yaml:"server"`
+		Database TestDatabase `yaml:"database"`
+		Level    string       `yaml:"level" default:"info"`
+		Region   string       `yaml:"region" default:"eu"`
+		Token    string       `yaml:"token"`
+	}
+
+	var config Config
+	sources, err := Explain(&config,
+		WithPaths("./test"),
+		WithName("config"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_SERVER_PORT": "9090"}),
+		WithOverrides([]string{"region=us"}),
+	)
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"Server.Port":   "env",
+		"Server.Host":   "file",
+		"Database.Name": "file",
+		"Level":         "default",
+		"Region":        "override",
+		"Token":         "unset",
+	}
+	for path, source := range expected {
+		if sources[path] != source {
+			t.Errorf("Expected %s from %s, got %q", path, source, sources[path])
+		}
+	}
+
+	// Explain загружает конфигурацию как Load
+	if config.Server.Port != 9090 || config.Region != "us" {
+		t.Errorf("Expected loaded config, got port %d, region %s", config.Server.Port, config.Region)
+	}
+}
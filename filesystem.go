@@ -0,0 +1,197 @@
+package cfg
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// fileSystem is a filesystem searched for config files.
+type fileSystem interface {
+	ReadFile(name string) ([]byte, error)
+	Glob(pattern string) ([]string, error)
+	Stat(name string) (fs.FileInfo, error)
+}
+
+// osFileSystem reads files from the OS filesystem.
+type osFileSystem struct{}
+
+func (osFileSystem) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+func (osFileSystem) Glob(pattern string) ([]string, error) {
+	return filepath.Glob(pattern)
+}
+
+func (osFileSystem) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+// fsFileSystem reads files from fs.FS. Names that are not valid in fs.FS
+// (e.g. absolute paths) are reported as not existing.
+type fsFileSystem struct {
+	fsys fs.FS
+}
+
+func (f fsFileSystem) ReadFile(name string) ([]byte, error) {
+	name, ok := fsName(name)
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return fs.ReadFile(f.fsys, name)
+}
+
+func (f fsFileSystem) Glob(pattern string) ([]string, error) {
+	pattern, ok := fsName(pattern)
+	if !ok {
+		return nil, nil
+	}
+	return fs.Glob(f.fsys, pattern)
+}
+
+func (f fsFileSystem) Stat(name string) (fs.FileInfo, error) {
+	name, ok := fsName(name)
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return fs.Stat(f.fsys, name)
+}
+
+func fsName(name string) (string, bool) {
+	name = path.Clean(filepath.ToSlash(name))
+	return name, fs.ValidPath(name)
+}
+
+// expandSearchPaths expands environment variables and a leading ~ in search paths.
+func expandSearchPaths(params *parameters) ([]string, error) {
+	paths := make([]string, len(params.paths))
+	for i, p := range params.paths {
+		p = os.Expand(p, func(name string) string {
+			value, _ := params.lookupEnv(name)
+			return value
+		})
+
+		if p == "~" || strings.HasPrefix(p, "~/") || strings.HasPrefix(p, `~\`) {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, fmt.Errorf("expand path %s: %w", p, err)
+			}
+			p = filepath.Join(home, p[1:])
+		}
+
+		paths[i] = p
+	}
+
+	if params.xdgConfigApp != "" {
+		if dir, ok := xdgConfigDir(params); ok {
+			paths = append(paths, filepath.Join(dir, params.xdgConfigApp))
+		}
+	}
+	return paths, nil
+}
+
+// xdgConfigDir returns the user config directory: an absolute $XDG_CONFIG_HOME, else
+// ~/.config on Unix and os.UserConfigDir (e.g. ~/Library/Application Support,
+// %AppData%) on macOS and Windows. It reports false when none can be resolved.
+func xdgConfigDir(params *parameters) (string, bool) {
+	if dir, ok := params.lookupEnv("XDG_CONFIG_HOME"); ok && filepath.IsAbs(dir) {
+		return dir, true
+	}
+
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		dir, err := os.UserConfigDir()
+		return dir, err == nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", false
+	}
+	return filepath.Join(home, ".config"), true
+}
+
+// expandPaths expands brace groups ({a,b}) and glob patterns in search paths.
+// Only directories matched by a pattern are searched, a pattern that matches
+// nothing is skipped like a missing path. Plain paths are kept as is.
+func expandPaths(fsys fileSystem, paths []string) ([]string, error) {
+	var dirs []string
+	for _, p := range paths {
+		for _, expanded := range expandBraces(p) {
+			if !hasGlobMeta(expanded) {
+				dirs = append(dirs, expanded)
+				continue
+			}
+
+			matches, err := fsys.Glob(expanded)
+			if err != nil {
+				return nil, fmt.Errorf("invalid path pattern %s: %w", expanded, err)
+			}
+
+			for _, match := range matches {
+				if info, err := fsys.Stat(match); err == nil && info.IsDir() {
+					dirs = append(dirs, match)
+				}
+			}
+		}
+	}
+	return dirs, nil
+}
+
+func hasGlobMeta(p string) bool {
+	return strings.ContainsAny(p, "*?[")
+}
+
+// expandBraces expands the first {a,b,...} group of p and recurses into the results,
+// so "conf/{base,local}" becomes "conf/base" and "conf/local".
+func expandBraces(p string) []string {
+	start := strings.IndexByte(p, '{')
+	if start < 0 {
+		return []string{p}
+	}
+
+	depth := 0
+	end := -1
+	for i := start; i < len(p) && end < 0; i++ {
+		switch p[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				end = i
+			}
+		}
+	}
+	if end < 0 {
+		return []string{p}
+	}
+
+	var alternatives []string
+	depth = 0
+	last := start + 1
+	for i := start + 1; i < end; i++ {
+		switch p[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				alternatives = append(alternatives, p[last:i])
+				last = i + 1
+			}
+		}
+	}
+	alternatives = append(alternatives, p[last:end])
+
+	var result []string
+	for _, alternative := range alternatives {
+		result = append(result, expandBraces(p[:start]+alternative+p[end+1:])...)
+	}
+	return result
+}
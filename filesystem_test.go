@@ -0,0 +1,156 @@
+package cfg
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestGlobPaths(t *testing.T) {
+	t.Parallel()
+
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithPaths("./test/conf.d/*"),
+		WithName("config"),
+		WithMerge(),
+		WithEnv(map[string]string{}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.App.Name != "base-app" {
+		t.Errorf("Expected app.name 'base-app' from 10-base, got '%s'", cfg.App.Name)
+	}
+
+	if cfg.Server.Port != 2000 {
+		t.Errorf("Expected server.port 2000 from 20-local, got %d", cfg.Server.Port)
+	}
+}
+
+func TestGlobPathsWithoutMatches(t *testing.T) {
+	t.Parallel()
+
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithPaths("./whereAreYou/*", "./test/{override,missing}"),
+		WithName("config"),
+		WithEnv(map[string]string{}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Server.Port != 8080 {
+		t.Errorf("Expected server.port 8080 from test/override, got %d", cfg.Server.Port)
+	}
+}
+
+func TestExpandBraces(t *testing.T) {
+	tests := map[string][]string{
+		"conf":                {"conf"},
+		"conf/{a,b}":          {"conf/a", "conf/b"},
+		"{x,y}/{a,b}":         {"x/a", "x/b", "y/a", "y/b"},
+		"conf/{a,{b,c}}/dir":  {"conf/a/dir", "conf/b/dir", "conf/c/dir"},
+		"conf/{unterminated":  {"conf/{unterminated"},
+		"conf/{single}/local": {"conf/single/local"},
+	}
+
+	for pattern, expected := range tests {
+		if got := expandBraces(pattern); !reflect.DeepEqual(got, expected) {
+			t.Errorf("expandBraces(%q): expected %v, got %v", pattern, expected, got)
+		}
+	}
+}
+
+func TestExpandSearchPaths(t *testing.T) {
+	home, err := filepath.Abs("./test/home")
+	if err != nil {
+		t.Fatalf("Failed to resolve home dir: %v", err)
+	}
+
+	oldHome, hadHome := os.LookupEnv("HOME")
+	err = os.Setenv("HOME", home)
+	if err != nil {
+		t.Fatalf("Failed to set env HOME: %v", err)
+	}
+	defer func() {
+		if hadHome {
+			_ = os.Setenv("HOME", oldHome)
+		} else {
+			_ = os.Unsetenv("HOME")
+		}
+	}()
+
+	tests := map[string]string{
+		"~/.config/app":     "home-app",
+		"$HOME/.config/app": "home-app",
+		"${HOME}/.config/":  "",
+		"./test":            "test-app",
+	}
+
+	for path, expected := range tests {
+		var cfg TestConfig
+
+		err := Load(&cfg,
+			WithPaths(path),
+			WithName("config"),
+		)
+
+		if err != nil {
+			t.Fatalf("Load failed for path %s: %v", path, err)
+		}
+
+		if cfg.App.Name != expected {
+			t.Errorf("Expected app.name '%s' for path %s, got '%s'", expected, path, cfg.App.Name)
+		}
+	}
+}
+
+func TestXDGConfig(t *testing.T) {
+	home, err := filepath.Abs("./test/home")
+	if err != nil {
+		t.Fatalf("Failed to resolve home dir: %v", err)
+	}
+
+	// XDG_CONFIG_HOME имеет приоритет
+	var cfg TestConfig
+	err = Load(&cfg,
+		WithPaths("./nonexistent"),
+		WithXDGConfig("app"),
+		WithEnv(map[string]string{"XDG_CONFIG_HOME": filepath.Join(home, ".config")}),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.App.Name != "home-app" {
+		t.Errorf("Expected app.name home-app from XDG_CONFIG_HOME, got %q", cfg.App.Name)
+	}
+
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		return
+	}
+
+	// Без XDG_CONFIG_HOME (или с относительным путем) используется ~/.config
+	t.Setenv("HOME", home)
+
+	var fallback TestConfig
+	err = Load(&fallback,
+		WithPaths("./nonexistent"),
+		WithXDGConfig("app"),
+		WithEnv(map[string]string{"XDG_CONFIG_HOME": "relative"}),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if fallback.App.Name != "home-app" {
+		t.Errorf("Expected app.name home-app from ~/.config, got %q", fallback.App.Name)
+	}
+}
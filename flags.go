@@ -0,0 +1,113 @@
+package cfg
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+)
+
+// loadFromFlags overrides fields tagged flag:"name" with flags that were
+// explicitly set on the command line. Unset flags keep the loaded values.
+func loadFromFlags(cfg any, params *parameters) error {
+	if params.flags == nil {
+		return nil
+	}
+
+	set := make(map[string]string)
+	params.flags.Visit(func(f *flag.Flag) {
+		set[f.Name] = f.Value.String()
+	})
+
+	if len(set) == 0 {
+		return nil
+	}
+
+	_, err := loadStructFromFlags(reflect.ValueOf(cfg).Elem(), set, params, "")
+	return err
+}
+
+func loadStructFromFlags(v reflect.Value, set map[string]string, params *parameters, path string) (bool, error) {
+	t := v.Type()
+	changed := false
+	defer params.enterWalk(t)()
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		structField := t.Field(i)
+
+		if structField.Anonymous && field.Kind() == reflect.Struct {
+			fieldChanged, err := loadStructFromFlags(field, set, params, path)
+			if err != nil {
+				return false, err
+			}
+			changed = changed || fieldChanged
+			continue
+		}
+
+		if !field.CanSet() {
+			continue
+		}
+
+		fieldPath := joinFieldPath(path, structField.Name)
+
+		if isNestedStruct(field) {
+			fieldChanged, err := loadStructFromFlags(field, set, params, fieldPath)
+			if err != nil {
+				return false, err
+			}
+			changed = changed || fieldChanged
+			continue
+		}
+
+		if isStructPtr(field) {
+			fieldChanged, err := loadStructPtrFromFlags(field, set, params, fieldPath)
+			if err != nil {
+				return false, err
+			}
+			changed = changed || fieldChanged
+			continue
+		}
+
+		name := structField.Tag.Get("flag")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		value, ok := set[name]
+		if !ok {
+			continue
+		}
+
+		if err := setStructField(field, structField, value, params); err != nil {
+			return false, fmt.Errorf("field %s: flag -%s: %w", fieldPath, name, err)
+		}
+		changed = true
+	}
+
+	return changed, nil
+}
+
+// loadStructPtrFromFlags fills a pointer to struct, allocating it only when
+// at least one of its fields was set by a flag.
+func loadStructPtrFromFlags(field reflect.Value, set map[string]string, params *parameters, path string) (bool, error) {
+	if !field.IsNil() {
+		return loadStructFromFlags(field.Elem(), set, params, path)
+	}
+
+	// рекурсивный тип (Fallback *Node внутри Node) не аллоцируем бесконечно
+	if params.onWalkPath(field.Type()) {
+		return false, nil
+	}
+
+	ptr := reflect.New(field.Type().Elem())
+	changed, err := loadStructFromFlags(ptr.Elem(), set, params, path)
+	if err != nil {
+		return false, err
+	}
+
+	if changed {
+		field.Set(ptr)
+	}
+
+	return changed, nil
+}
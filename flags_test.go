@@ -0,0 +1,106 @@
+package cfg
+
+import (
+	"flag"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadWithFlags(t *testing.T) {
+	type Server struct {
+		Host string `yaml:"host" env:"SERVER_HOST" flag:"host"`
+		Port int    `yaml:"port" env:"SERVER_PORT" flag:"port"`
+	}
+	type Config struct {
+		Server  Server        `yaml:"server"`
+		Timeout time.Duration `yaml:"timeout" flag:"timeout"`
+		Debug   bool          `yaml:"debug" flag:"debug"`
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("host", "flag-default", "")
+	fs.Int("port", 0, "")
+	fs.Duration("timeout", 0, "")
+	fs.Bool("debug", false, "")
+	if err := fs.Parse([]string{"-port", "9999", "-timeout", "5s"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	var config Config
+	err := Load(&config,
+		WithPaths("./test"),
+		WithName("config"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{
+			"TEST_SERVER_HOST": "env-host",
+			"TEST_SERVER_PORT": "7000",
+		}),
+		WithFlags(fs),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Флаг переопределяет env
+	if config.Server.Port != 9999 {
+		t.Errorf("Expected server.port 9999 from flag, got %d", config.Server.Port)
+	}
+
+	// Незаданный флаг не затирает значение из env
+	if config.Server.Host != "env-host" {
+		t.Errorf("Expected server.host env-host, got %s", config.Server.Host)
+	}
+
+	if config.Timeout != 5*time.Second {
+		t.Errorf("Expected timeout 5s, got %s", config.Timeout)
+	}
+
+	if config.Debug {
+		t.Error("Expected debug to stay false")
+	}
+}
+
+func TestLoadWithFlagsInvalid(t *testing.T) {
+	type Config struct {
+		Port int `yaml:"port" flag:"port"`
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("port", "", "")
+	if err := fs.Parse([]string{"-port", "abc"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	var config Config
+	err := Load(&config, WithPaths("./nonexistent"), WithFlags(fs))
+	if err == nil {
+		t.Fatal("Expected error for invalid flag value")
+	}
+
+	if !strings.Contains(err.Error(), "flag -port") {
+		t.Errorf("Expected error to mention flag -port, got %v", err)
+	}
+}
+
+func TestLoadWithFlagsRecursiveType(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("name", "", "")
+	if err := fs.Parse([]string{"-name", "flag-node"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	var config testNode
+	err := Load(&config,
+		WithoutFile(),
+		WithEnv(map[string]string{}),
+		WithFlags(fs),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if config.Name != "flag-node" || config.Fallback != nil {
+		t.Errorf("Expected name flag-node without fallback, got %+v", config)
+	}
+}
@@ -0,0 +1,115 @@
+package cfg
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// includeTag marks a YAML value replaced by the content of another file.
+const includeTag = "!include"
+
+// maxIncludeDepth limits nested includes.
+const maxIncludeDepth = 10
+
+// includeResolver inlines !include values. stack holds the chain of files being
+// included to detect cycles, files collects every included file.
+type includeResolver struct {
+	fsys   fileSystem
+	params *parameters
+	stack  []string
+	files  []string
+}
+
+// resolveIncludes replaces every `!include path` value in the YAML file with the
+// content of path, resolved relative to the including file. It returns the data
+// and the included files.
+func resolveIncludes(data []byte, file string, fsys fileSystem, params *parameters) ([]byte, []string, error) {
+	if !bytes.Contains(data, []byte(includeTag)) {
+		return data, nil, nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, err
+	}
+
+	r := &includeResolver{fsys: fsys, params: params, stack: []string{filepath.Clean(file)}}
+	if err := r.resolve(&doc, filepath.Dir(file)); err != nil {
+		return nil, nil, err
+	}
+
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, r.files, nil
+}
+
+func (r *includeResolver) resolve(node *yaml.Node, dir string) error {
+	if node.Tag == includeTag {
+		return r.include(node, dir)
+	}
+
+	for _, child := range node.Content {
+		if err := r.resolve(child, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *includeResolver) include(node *yaml.Node, dir string) error {
+	if node.Kind != yaml.ScalarNode || node.Value == "" {
+		return fmt.Errorf("line %d: %s expects a file path", node.Line, includeTag)
+	}
+
+	file := node.Value
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(dir, file)
+	}
+
+	if slices.Contains(r.stack, file) {
+		return fmt.Errorf("include cycle: %s", strings.Join(append(r.stack, file), " -> "))
+	}
+	if len(r.stack) > maxIncludeDepth {
+		return fmt.Errorf("include %s: nested deeper than %d files", file, maxIncludeDepth)
+	}
+
+	data, err := r.fsys.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("include %s: %w", file, err)
+	}
+
+	if _, ok := r.fsys.(osFileSystem); ok {
+		if err := checkFileMode(file, r.params); err != nil {
+			return fmt.Errorf("include %s: %w", file, err)
+		}
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("include %s: %w", file, err)
+	}
+
+	r.stack = append(r.stack, file)
+	err = r.resolve(&doc, filepath.Dir(file))
+	r.stack = r.stack[:len(r.stack)-1]
+	if err != nil {
+		return err
+	}
+
+	r.files = append(r.files, file)
+
+	// пустой файл подставляется как null
+	if len(doc.Content) == 0 {
+		*node = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}
+		return nil
+	}
+	*node = *doc.Content[0]
+	return nil
+}
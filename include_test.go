@@ -0,0 +1,92 @@
+package cfg
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestYamlInclude(t *testing.T) {
+	var cfg TestConfig
+
+	err := Load(&cfg,
+		WithPaths("./test/include"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_SERVER_PORT": "5000"}),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.App.Name != "include-app" || cfg.Server.Host != "included.localhost" {
+		t.Errorf("Expected values from included files, got %+v %+v", cfg.App, cfg.Server)
+	}
+
+	// Вложенный include разрешается относительно включающего файла
+	if cfg.Database.Host != "db.included" || cfg.Database.Name != "included_db" {
+		t.Errorf("Expected database from nested include, got %+v", cfg.Database)
+	}
+
+	if cfg.Server.Port != 5000 {
+		t.Errorf("Expected server.port 5000 from env, got %d", cfg.Server.Port)
+	}
+}
+
+func TestYamlIncludeWatchedFiles(t *testing.T) {
+	var cfg TestConfig
+
+	p, err := load(t.Context(), &cfg, loadFromFile, []Action{
+		WithPaths("./test/include"),
+		WithEnv(map[string]string{}),
+	})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Включенные файлы отслеживаются Watch вместе с основным
+	expected := filepath.Join("test", "include", "db", "main.yaml")
+	if !slices.Contains(p.loadedFiles, expected) {
+		t.Errorf("Expected loaded files to contain %s, got %v", expected, p.loadedFiles)
+	}
+}
+
+func TestYamlIncludeErrors(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"cycle.yaml":   "server: !include cycle_b.yaml\n",
+		"cycle_b.yaml": "!include cycle.yaml\n",
+		"missing.yaml": "server: !include nowhere.yaml\n",
+		"mapping.yaml": "server: !include {path: a.yaml}\n",
+	}
+	// Цепочка длиннее maxIncludeDepth
+	for i := 0; i <= maxIncludeDepth+1; i++ {
+		files["deep"+strconv.Itoa(i)+".yaml"] = "!include deep" + strconv.Itoa(i+1) + ".yaml\n"
+	}
+
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"cycle", "include cycle: "},
+		{"missing", "include " + filepath.Join(dir, "nowhere.yaml")},
+		{"mapping", "line 1: !include expects a file path"},
+		{"deep0", "nested deeper than 10 files"},
+	}
+
+	for _, tt := range tests {
+		var cfg TestConfig
+		err := Load(&cfg, WithPaths(dir), WithName(tt.name), WithEnv(map[string]string{}))
+		if err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("Expected error containing %q for %s, got %v", tt.expected, tt.name, err)
+		}
+	}
+}
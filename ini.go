@@ -0,0 +1,115 @@
+package cfg
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// iniEntry is a value or a section of an INI / properties file.
+type iniEntry struct {
+	value    string
+	children map[string]*iniEntry
+	keys     []string
+}
+
+// unmarshalIni decodes INI and Java properties files: key=value lines, optionally
+// grouped in [section] headers, with # and ; comments. Sections and dotted keys map
+// to nested fields, a repeated key overrides the previous one. The entries are
+// converted to YAML, so the yaml tags and WithStrict apply as for YAML files.
+func unmarshalIni(data []byte, v any, params *parameters) error {
+	root := &iniEntry{children: make(map[string]*iniEntry)}
+	section := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]") {
+			section = strings.TrimSpace(text[1 : len(text)-1])
+			continue
+		}
+
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return fmt.Errorf("line %d: expected key=value, got %q", line, text)
+		}
+
+		key = strings.TrimSpace(key)
+		if section != "" {
+			key = section + "." + key
+		}
+
+		if err := root.set(strings.Split(key, "."), unquoteIniValue(strings.TrimSpace(value))); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	out, err := yaml.Marshal(root.node())
+	if err != nil {
+		return err
+	}
+	return unmarshalYaml(out, v, params)
+}
+
+func (e *iniEntry) set(keys []string, value string) error {
+	entry := e
+	for i, key := range keys {
+		child, ok := entry.children[key]
+		if !ok {
+			child = &iniEntry{}
+			entry.children[key] = child
+			entry.keys = append(entry.keys, key)
+		}
+
+		last := i == len(keys)-1
+		if last && child.children != nil || !last && ok && child.children == nil {
+			return fmt.Errorf("key %s is both a value and a section", strings.Join(keys[:i+1], "."))
+		}
+
+		if last {
+			child.value = value
+			return nil
+		}
+
+		if child.children == nil {
+			child.children = make(map[string]*iniEntry)
+		}
+		entry = child
+	}
+	return nil
+}
+
+// node converts the entry to a YAML node with plain scalars, so values are
+// resolved like unquoted YAML values (8080 is an int, true is a bool).
+func (e *iniEntry) node() *yaml.Node {
+	if e.children == nil {
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: e.value}
+	}
+
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range e.keys {
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: key},
+			e.children[key].node(),
+		)
+	}
+	return node
+}
+
+// unquoteIniValue strips matching quotes around a value.
+func unquoteIniValue(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
@@ -0,0 +1,33 @@
+package cfg
+
+import "context"
+
+// Loader loads configurations with a fixed set of options, e.g. when a service
+// loads several related configs with the same prefix and paths.
+// A Loader is safe for concurrent use.
+type Loader struct {
+	paramsActions []Action
+}
+
+// New returns a Loader that applies paramsActions on every load.
+func New(paramsActions ...Action) *Loader {
+	return &Loader{paramsActions: append([]Action(nil), paramsActions...)}
+}
+
+// Load downloads the configuration into cfg, see Load.
+func (l *Loader) Load(cfg any) error {
+	return l.LoadContext(context.Background(), cfg)
+}
+
+// LoadContext downloads the configuration into cfg, see LoadContext.
+func (l *Loader) LoadContext(ctx context.Context, cfg any) error {
+	_, err := load(ctx, cfg, loadFromFile, l.paramsActions)
+	return err
+}
+
+// MustLoad downloads the configuration into cfg or panics.
+func (l *Loader) MustLoad(cfg any) {
+	if err := l.Load(cfg); err != nil {
+		panic("cfg: failed to load config: " + err.Error())
+	}
+}
@@ -0,0 +1,92 @@
+package cfg
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestLoader(t *testing.T) {
+	t.Parallel()
+
+	loader := New(
+		WithPaths("./test"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_SERVER_PORT": "9000"}),
+	)
+
+	// Один загрузчик переиспользуется для нескольких конфигов
+	var first, second TestConfig
+	if err := loader.Load(&first); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if err := loader.Load(&second); err != nil {
+		t.Fatalf("Second Load failed: %v", err)
+	}
+
+	for _, cfg := range []TestConfig{first, second} {
+		if cfg.App.Name != "test-app" {
+			t.Errorf("Expected app.name test-app, got %s", cfg.App.Name)
+		}
+		if cfg.Server.Port != 9000 {
+			t.Errorf("Expected server.port 9000, got %d", cfg.Server.Port)
+		}
+	}
+}
+
+func TestLoaderInvalidOption(t *testing.T) {
+	t.Parallel()
+
+	var cfg TestConfig
+	if err := New(WithEnvPrefix("BAD-PREFIX")).Load(&cfg); err == nil {
+		t.Error("Expected error for invalid option")
+	}
+}
+
+func TestLoaderMustLoadPanic(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected MustLoad to panic on missing config")
+		}
+	}()
+
+	var cfg TestConfig
+	New(WithPaths("./test"), WithName("missing"), WithRequireFile()).MustLoad(&cfg)
+}
+
+func TestLoaderConcurrentLoad(t *testing.T) {
+	t.Parallel()
+
+	// Опции переиспользуются при каждом Load, запускать с -race
+	loader := New(
+		WithPaths("./test"),
+		WithEnvPrefix("TEST"),
+		WithDotEnv(),
+		WithStrictEnv(),
+		WithEnv(map[string]string{"TEST_SERVER_PORT": "9000"}),
+	)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var cfg TestConfig
+			if err := loader.Load(&cfg); err != nil {
+				errs <- err
+				return
+			}
+			if cfg.Server.Port != 9000 {
+				t.Errorf("Expected server.port 9000, got %d", cfg.Server.Port)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("Load failed: %v", err)
+	}
+}
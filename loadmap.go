@@ -0,0 +1,73 @@
+package cfg
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envKeyReplacer maps characters not allowed in env names to the separator.
+var envKeyReplacer = strings.NewReplacer("-", "_", ".", "_")
+
+// LoadMap loads the config file into a generic map, for tools that do not know the
+// schema at compile time. Keys found in the file are overridden by env variables
+// named after their path, e.g. server.port by APP_SERVER_PORT; env values are typed
+// like YAML scalars. Options that need a struct (defaults, flags, validation) are ignored.
+func LoadMap(paramsActions ...Action) (map[string]any, error) {
+	p, err := newParameters(paramsActions)
+	if err != nil {
+		return nil, err
+	}
+	p.ctx = context.Background()
+
+	if err := loadDotEnv(p); err != nil {
+		return nil, fmt.Errorf("load dotenv: %w", err)
+	}
+
+	values := make(map[string]any)
+	if err := loadFromFile(&values, p); err != nil {
+		return nil, fmt.Errorf("unload config file: %w", err)
+	}
+
+	if !p.withoutEnv {
+		loadMapValuesFromEnv(values, p.envPrefix, p)
+	}
+
+	return values, nil
+}
+
+// loadMapValuesFromEnv overrides the values of m, recursing into nested maps.
+func loadMapValuesFromEnv(m map[string]any, envPrefix string, params *parameters) {
+	for key, value := range m {
+		envVar := prefixEnvName(strings.ToUpper(envKeyReplacer.Replace(key)), envPrefix, params.prefixSeparator)
+
+		if nested, ok := value.(map[string]any); ok {
+			loadMapValuesFromEnv(nested, envVar, params)
+			continue
+		}
+
+		if envValue, ok := params.lookupEnv(envVar); ok {
+			if envValue = params.trimEnvValue(envValue); envValue != "" || !params.ignoreEmptyEnv {
+				m[key] = parseScalar(envValue)
+			}
+		}
+	}
+}
+
+// parseScalar types value like a plain YAML scalar: 8080 is an int, true a bool.
+// Anything else, including YAML collections, stays a string.
+func parseScalar(value string) any {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &node); err != nil || len(node.Content) == 0 ||
+		node.Content[0].Kind != yaml.ScalarNode {
+		return value
+	}
+
+	var parsed any
+	if err := node.Content[0].Decode(&parsed); err != nil {
+		return value
+	}
+	return parsed
+}
@@ -0,0 +1,48 @@
+package cfg
+
+import "testing"
+
+func TestLoadMap(t *testing.T) {
+	values, err := LoadMap(
+		WithPaths("./test"),
+		WithName("config"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{
+			"TEST_SERVER_PORT": "9090",
+			"TEST_APP_NAME":    "env-app",
+			"TEST_UNKNOWN_KEY": "ignored",
+		}),
+	)
+	if err != nil {
+		t.Fatalf("LoadMap failed: %v", err)
+	}
+
+	server, ok := values["server"].(map[string]any)
+	if !ok {
+		t.Fatalf("Expected server section to be a map, got %T", values["server"])
+	}
+
+	// Значение из env типизируется как скаляр YAML
+	if server["port"] != 9090 {
+		t.Errorf("Expected server.port 9090 from env, got %v (%T)", server["port"], server["port"])
+	}
+
+	if server["host"] != "localhost" || server["debug"] != true {
+		t.Errorf("Expected server values from file, got %v", server)
+	}
+
+	if app := values["app"].(map[string]any); app["name"] != "env-app" {
+		t.Errorf("Expected app.name env-app, got %v", app["name"])
+	}
+
+	if _, ok := values["unknown"]; ok {
+		t.Error("Expected env variables without a key in the file to be ignored")
+	}
+}
+
+func TestLoadMapRequireFile(t *testing.T) {
+	_, err := LoadMap(WithPaths("./test"), WithName("missing"), WithRequireFile())
+	if err == nil {
+		t.Error("Expected error for missing required file")
+	}
+}
@@ -0,0 +1,75 @@
+package cfg
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// loadFromOverrides sets fields addressed by dotted file keys, e.g. server.port=9090.
+func loadFromOverrides(cfg any, params *parameters) error {
+	for _, override := range params.overrides {
+		key, value, ok := strings.Cut(override, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid override %q, expected key=value", override)
+		}
+
+		field, structField, path, found := findOverrideField(reflect.ValueOf(cfg).Elem(), strings.Split(key, "."), params, "")
+		if !found {
+			return fmt.Errorf("unknown override key %q", key)
+		}
+
+		if err := setStructField(field, structField, value, params); err != nil {
+			return fmt.Errorf("field %s: override %s: %w", path, key, err)
+		}
+	}
+
+	return nil
+}
+
+// findOverrideField looks up the field addressed by keys. Nil pointers to
+// structs on the way are allocated only when the field is found.
+func findOverrideField(v reflect.Value, keys []string, params *parameters, path string) (reflect.Value, reflect.StructField, string, bool) {
+	t := v.Type()
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		structField := t.Field(i)
+
+		if isInlined(structField) {
+			if found, foundField, foundPath, ok := findOverrideField(field, keys, params, path); ok {
+				return found, foundField, foundPath, true
+			}
+			continue
+		}
+
+		if !field.CanSet() || taggedKey(structField, params) != keys[0] {
+			continue
+		}
+
+		fieldPath := joinFieldPath(path, structField.Name)
+
+		if len(keys) == 1 {
+			return field, structField, fieldPath, true
+		}
+
+		if isNestedStruct(field) {
+			return findOverrideField(field, keys[1:], params, fieldPath)
+		}
+
+		if isStructPtr(field) {
+			if !field.IsNil() {
+				return findOverrideField(field.Elem(), keys[1:], params, fieldPath)
+			}
+
+			ptr := reflect.New(field.Type().Elem())
+			found, foundField, foundPath, ok := findOverrideField(ptr.Elem(), keys[1:], params, fieldPath)
+			if ok {
+				field.Set(ptr)
+			}
+			return found, foundField, foundPath, ok
+		}
+	}
+
+	return reflect.Value{}, reflect.StructField{}, "", false
+}
@@ -0,0 +1,65 @@
+package cfg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadWithOverrides(t *testing.T) {
+	type Limits struct {
+		Max int `yaml:"max"`
+	}
+	type Config struct {
+		Server TestServer `yaml:"server"`
+		Limits *Limits    `yaml:"limits"`
+	}
+
+	var config Config
+	err := Load(&config,
+		WithPaths("./test"),
+		WithName("config"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_SERVER_PORT": "7000"}),
+		WithOverrides([]string{"server.port=9090", "server.host=override", "limits.max=5"}),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Override переопределяет env
+	if config.Server.Port != 9090 {
+		t.Errorf("Expected server.port 9090 from override, got %d", config.Server.Port)
+	}
+
+	if config.Server.Host != "override" {
+		t.Errorf("Expected server.host override, got %s", config.Server.Host)
+	}
+
+	if config.Limits == nil || config.Limits.Max != 5 {
+		t.Errorf("Expected limits.max 5, got %+v", config.Limits)
+	}
+}
+
+func TestLoadWithOverridesErrors(t *testing.T) {
+	tests := []struct {
+		override string
+		expected string
+	}{
+		{"server.missing=1", `unknown override key "server.missing"`},
+		{"server", "expected key=value"},
+		{"server.port=abc", "field Server.Port: override server.port"},
+	}
+
+	for _, tt := range tests {
+		var config TestConfig
+		err := Load(&config,
+			WithPaths("./test"),
+			WithName("config"),
+			WithEnv(map[string]string{}),
+			WithOverrides([]string{tt.override}),
+		)
+		if err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("Expected error containing %q for %s, got %v", tt.expected, tt.override, err)
+		}
+	}
+}
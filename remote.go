@@ -0,0 +1,84 @@
+package cfg
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// defaultHTTPClient is used by WithURL unless WithHTTPClient is set.
+var defaultHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// loadFromURL fetches the config from params.url and decodes it.
+func loadFromURL(cfg any, params *parameters) error {
+	client := params.httpClient
+	if client == nil {
+		client = defaultHTTPClient
+	}
+
+	req, err := http.NewRequestWithContext(params.ctx, http.MethodGet, params.url, nil)
+	if err != nil {
+		return fmt.Errorf("fetch %s: %w", params.url, err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetch %s: %w", params.url, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetch %s: unexpected status %s", params.url, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("fetch %s: %w", params.url, err)
+	}
+
+	f, err := formatForURL(params.url, resp.Header.Get("Content-Type"), params)
+	if err != nil {
+		return err
+	}
+
+	if name, err := unmarshalData(f, data, cfg, params); err != nil {
+		return &ParseError{File: params.url, Format: name, Err: err}
+	}
+
+	return nil
+}
+
+// formatForURL picks the format from WithFormat, then the Content-Type header,
+// then the extension of the URL path, detecting it from the content otherwise.
+func formatForURL(rawURL, contentType string, params *parameters) (format, error) {
+	if params.format != "" {
+		return formatForFile("", params)
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch {
+		case strings.HasSuffix(mediaType, "json"):
+			f, _ := formatByName("json")
+			return f, nil
+		case strings.HasSuffix(mediaType, "yaml"):
+			f, _ := formatByName("yaml")
+			return f, nil
+		case strings.HasSuffix(mediaType, "toml"):
+			f, _ := formatByName("toml")
+			return f, nil
+		}
+	}
+
+	name := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		name = path.Base(u.Path)
+	}
+	return formatForFile(name, params)
+}
@@ -0,0 +1,76 @@
+package cfg
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadFromURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/config":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			_, _ = w.Write([]byte(`{"app": {"name": "remote-app"}, "server": {"port": 7000}}`))
+		case "/config.yaml":
+			_, _ = w.Write([]byte("app:\n  name: yaml-app\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	var cfg TestConfig
+	err := Load(&cfg,
+		WithURL(server.URL+"/config"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_SERVER_HOST": "env-host"}),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.App.Name != "remote-app" || cfg.Server.Port != 7000 {
+		t.Errorf("Expected remote-app on port 7000 from JSON body, got %s:%d", cfg.App.Name, cfg.Server.Port)
+	}
+
+	// env переопределяет загруженную конфигурацию
+	if cfg.Server.Host != "env-host" {
+		t.Errorf("Expected server.host env-host from env, got %s", cfg.Server.Host)
+	}
+
+	var byExt TestConfig
+	if err := Load(&byExt, WithURL(server.URL+"/config.yaml"), WithEnv(map[string]string{})); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if byExt.App.Name != "yaml-app" {
+		t.Errorf("Expected yaml-app from URL extension, got %s", byExt.App.Name)
+	}
+
+	var missing TestConfig
+	err = Load(&missing, WithURL(server.URL+"/missing"), WithEnv(map[string]string{}))
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected error with 404 status, got %v", err)
+	}
+}
+
+func TestLoadFromURLWithHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte("app:\n  name: slow-app\n"))
+	}))
+	defer server.Close()
+
+	var cfg TestConfig
+	err := Load(&cfg,
+		WithURL(server.URL),
+		WithHTTPClient(&http.Client{Timeout: 50 * time.Millisecond}),
+		WithEnv(map[string]string{}),
+	)
+	if err == nil {
+		t.Fatal("Expected timeout error from custom client")
+	}
+}
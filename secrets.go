@@ -0,0 +1,81 @@
+package cfg
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// secretScheme marks string values resolved with WithSecretCommand.
+const secretScheme = "secret://"
+
+// resolveSecrets replaces every string value starting with secret:// with the
+// value returned by the resolver for the rest of the string.
+func resolveSecrets(v reflect.Value, params *parameters, path string) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return resolveSecrets(v.Elem(), params, path)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Field(i)
+			structField := t.Field(i)
+
+			fieldPath := path
+			if !structField.Anonymous {
+				fieldPath = joinFieldPath(path, structField.Name)
+			}
+
+			if field.CanSet() || structField.Anonymous {
+				if err := resolveSecrets(field, params, fieldPath); err != nil {
+					return err
+				}
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := resolveSecrets(v.Index(i), params, path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if !v.CanSet() || v.Type().Elem().Kind() != reflect.String {
+			return nil
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			value, err := resolveSecret(iter.Value().String(), params, joinFieldPath(path, key))
+			if err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), reflect.ValueOf(value).Convert(v.Type().Elem()))
+		}
+	case reflect.String:
+		if v.CanSet() {
+			value, err := resolveSecret(v.String(), params, path)
+			if err != nil {
+				return err
+			}
+			v.SetString(value)
+		}
+	}
+
+	return nil
+}
+
+func resolveSecret(value string, params *parameters, path string) (string, error) {
+	key, ok := strings.CutPrefix(value, secretScheme)
+	if !ok {
+		return value, nil
+	}
+
+	secret, err := params.secretResolver(key)
+	if err != nil {
+		return "", fmt.Errorf("field %s: resolve secret %q: %w", path, key, err)
+	}
+	return secret, nil
+}
@@ -0,0 +1,77 @@
+package cfg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWithSecretCommand(t *testing.T) {
+	type Config struct {
+		Database struct {
+			Password string `yaml:"password" env:"DB_PASSWORD"`
+			User     string `yaml:"user"`
+		} `yaml:"database"`
+		Tokens  []string          `yaml:"tokens"`
+		Headers map[string]string `yaml:"headers"`
+		APIKey  string            `yaml:"api_key" env:"API_KEY" required:"true"`
+	}
+
+	secrets := map[string]string{
+		"db/password": "s3cr3t",
+		"tokens/a":    "token-a",
+		"auth":        "Bearer xyz",
+		"api":         "key-from-vault",
+	}
+	resolver := func(key string) (string, error) {
+		if value, ok := secrets[key]; ok {
+			return value, nil
+		}
+		return "", errors.New("not found")
+	}
+
+	data := `
+database:
+  password: secret://db/password
+  user: admin
+tokens: [secret://tokens/a, plain]
+headers:
+  authorization: secret://auth
+`
+
+	var config Config
+	err := LoadReader(&config, strings.NewReader(data),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_API_KEY": "secret://api"}),
+		WithSecretCommand(resolver),
+	)
+	if err != nil {
+		t.Fatalf("LoadReader failed: %v", err)
+	}
+
+	if config.Database.Password != "s3cr3t" || config.Database.User != "admin" {
+		t.Errorf("Expected resolved password and plain user, got %+v", config.Database)
+	}
+
+	if config.Tokens[0] != "token-a" || config.Tokens[1] != "plain" {
+		t.Errorf("Expected tokens [token-a plain], got %v", config.Tokens)
+	}
+
+	if config.Headers["authorization"] != "Bearer xyz" {
+		t.Errorf("Expected resolved header, got %v", config.Headers)
+	}
+
+	// Значения из env тоже разрешаются
+	if config.APIKey != "key-from-vault" {
+		t.Errorf("Expected api_key from resolver, got '%s'", config.APIKey)
+	}
+
+	var missing Config
+	err = LoadReader(&missing, strings.NewReader("api_key: secret://unknown\n"),
+		WithEnv(map[string]string{}),
+		WithSecretCommand(resolver),
+	)
+	if err == nil || !strings.Contains(err.Error(), `field APIKey: resolve secret "unknown"`) {
+		t.Errorf("Expected resolve error for APIKey, got %v", err)
+	}
+}
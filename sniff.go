@@ -0,0 +1,65 @@
+package cfg
+
+import (
+	"bufio"
+	"bytes"
+	"reflect"
+	"strings"
+)
+
+// sniffFormats lists the formats tried, in order, for content without a known
+// extension or WithFormat.
+var sniffFormats = []string{"yaml", "json", "toml"}
+
+// autoFormat marks content whose format is detected by unmarshalData.
+var autoFormat = format{name: "auto"}
+
+// unmarshalData decodes data into v with f and returns the name of the format used.
+// For autoFormat it picks the first of sniffFormats that decodes data cleanly into a
+// scratch value of the type of v, so a failed attempt never leaves partial values in v.
+// When every format fails, the error of the format data looks most like is returned.
+func unmarshalData(f format, data []byte, v any, params *parameters) (string, error) {
+	if f.name != autoFormat.name {
+		return f.name, f.unmarshal(data, v, params)
+	}
+
+	errs := make(map[string]error)
+	for _, name := range sniffFormats {
+		f, _ := formatByName(name)
+		scratch := reflect.New(reflect.TypeOf(v).Elem()).Interface()
+		if err := f.unmarshal(data, scratch, params); err != nil {
+			errs[name] = err
+			continue
+		}
+		return name, f.unmarshal(data, v, params)
+	}
+
+	name := guessFormat(data)
+	return name, errs[name]
+}
+
+// guessFormat names the format data most likely is, judged by its first line:
+// "{" or "[" opens json, "[section]" or "key = value" is toml, anything else yaml.
+func guessFormat(data []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		switch {
+		case line == "{" || line == "[" || strings.HasPrefix(line, "{\"") || strings.HasPrefix(line, "[{"):
+			return "json"
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") && !strings.Contains(line, ","):
+			return "toml"
+		}
+
+		key, _, ok := strings.Cut(line, "=")
+		if ok && !strings.Contains(key, ":") {
+			return "toml"
+		}
+		return "yaml"
+	}
+	return "yaml"
+}
@@ -0,0 +1,104 @@
+package cfg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSniffFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "yaml", data: "server:\n  port: 8080\n"},
+		{name: "json", data: `{"server": {"port": 8080}}`},
+		{name: "toml", data: "[server]\nport = 8080\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var cfg TestConfig
+			if err := LoadReader(&cfg, strings.NewReader(tt.data), WithEnv(map[string]string{})); err != nil {
+				t.Fatalf("LoadReader failed: %v", err)
+			}
+
+			if cfg.Server.Port != 8080 {
+				t.Errorf("Expected server.port 8080, got %d", cfg.Server.Port)
+			}
+		})
+	}
+}
+
+func TestSniffFormatConfigPath(t *testing.T) {
+	t.Parallel()
+
+	// Файл без расширения определяется по содержимому
+	file := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(file, []byte("[database]\nname = \"sniffed\"\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	var cfg TestConfig
+	err := Load(&cfg,
+		WithConfigPathEnv("TEST_CONFIG_FILE"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_CONFIG_FILE": file}),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Database.Name != "sniffed" {
+		t.Errorf("Expected database.name sniffed, got %q", cfg.Database.Name)
+	}
+}
+
+func TestSniffFormatErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		data   string
+		format string
+	}{
+		{name: "yaml", data: "server: [broken\n", format: "yaml"},
+		{name: "json", data: `{"server": {"port": "x"}}`, format: "json"},
+		{name: "toml", data: "[server]\nport = \"x\"\n", format: "toml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var cfg TestConfig
+			err := LoadReader(&cfg, strings.NewReader(tt.data), WithEnv(map[string]string{}))
+
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Expected ParseError, got %v", err)
+			}
+
+			if parseErr.Format != tt.format {
+				t.Errorf("Expected %s error, got format %q: %v", tt.format, parseErr.Format, err)
+			}
+		})
+	}
+}
+
+func TestSniffFormatWithFormat(t *testing.T) {
+	t.Parallel()
+
+	// С WithFormat содержимое не угадывается
+	var cfg TestConfig
+	err := LoadReader(&cfg, strings.NewReader("[server]\nport = 8080\n"), WithFormat("yaml"), WithEnv(map[string]string{}))
+	if err == nil {
+		t.Error("Expected yaml error for toml content with WithFormat(\"yaml\")")
+	}
+}
@@ -0,0 +1,50 @@
+package cfg
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// stdinName is the config name or config file path (WithConfigPathEnv) that reads the
+// config from standard input. Search paths set with WithPaths do not support it.
+const stdinName = "-"
+
+var (
+	stdin     io.Reader = os.Stdin
+	stdinOnce sync.Once
+	stdinData []byte
+	stdinErr  error
+)
+
+// readStdin reads standard input once, later loads (e.g. Watch reloads) reuse the data.
+func readStdin() ([]byte, error) {
+	stdinOnce.Do(func() {
+		stdinData, stdinErr = io.ReadAll(stdin)
+	})
+	return stdinData, stdinErr
+}
+
+// loadStdin decodes the config from standard input. The format is taken from
+// WithFormat or detected from the content.
+func loadStdin(cfg any, parameters *parameters) error {
+	f, err := formatForFile(stdinName, parameters)
+	if err != nil {
+		return err
+	}
+
+	data, err := readStdin()
+	if err != nil {
+		return fmt.Errorf("unread stdin: %w", err)
+	}
+
+	if err := checkEmptyFile(data, "stdin", parameters); err != nil {
+		return err
+	}
+
+	if name, err := unmarshalData(f, data, cfg, parameters); err != nil {
+		return &ParseError{File: "stdin", Format: name, Err: err}
+	}
+	return nil
+}
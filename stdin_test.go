@@ -0,0 +1,71 @@
+package cfg
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+// withStdin replaces standard input for the duration of the test.
+func withStdin(t *testing.T, data string) {
+	t.Helper()
+
+	original := stdin
+	stdin = strings.NewReader(data)
+	stdinOnce = sync.Once{}
+	t.Cleanup(func() {
+		stdin = original
+		stdinOnce = sync.Once{}
+	})
+}
+
+func TestLoadFromStdin(t *testing.T) {
+	withStdin(t, "server:\n  host: stdin.localhost\n  port: 1000\n")
+
+	var cfg TestConfig
+	err := Load(&cfg,
+		WithName("-"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_SERVER_PORT": "2000"}),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Server.Host != "stdin.localhost" {
+		t.Errorf("Expected server.host from stdin, got %s", cfg.Server.Host)
+	}
+
+	// Env по-прежнему переопределяет значения
+	if cfg.Server.Port != 2000 {
+		t.Errorf("Expected server.port 2000 from env, got %d", cfg.Server.Port)
+	}
+
+	// Stdin читается один раз, повторная загрузка получает те же данные
+	var again TestConfig
+	err = Load(&again,
+		WithConfigPathEnv("APP_CONFIG_FILE"),
+		WithEnv(map[string]string{"APP_CONFIG_FILE": "-"}),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if again.Server.Host != "stdin.localhost" {
+		t.Errorf("Expected server.host from stdin on second load, got %s", again.Server.Host)
+	}
+}
+
+func TestLoadFromStdinFormat(t *testing.T) {
+	withStdin(t, `{"server": {"port": 3000}}`)
+
+	var cfg TestConfig
+	err := Load(&cfg, WithName("-"), WithFormat("json"), WithEnv(map[string]string{}))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Server.Port != 3000 {
+		t.Errorf("Expected server.port 3000 from JSON stdin, got %d", cfg.Server.Port)
+	}
+}
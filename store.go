@@ -0,0 +1,70 @@
+package cfg
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Store holds the current configuration and is safe for concurrent use.
+// Readers get the latest loaded snapshot without locking, reloads load a fresh
+// value and atomically swap it in. Snapshots must be treated as read-only.
+type Store[T any] struct {
+	value         atomic.Pointer[T]
+	paramsActions []Action
+
+	// reloadMu serializes reloads, so a slower load never overwrites a newer one
+	reloadMu sync.Mutex
+
+	mu    sync.Mutex
+	files []string
+}
+
+// NewStore loads the configuration of type T into a new Store.
+func NewStore[T any](paramsActions ...Action) (*Store[T], error) {
+	s := &Store[T]{paramsActions: paramsActions}
+	if _, err := s.reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Get returns a copy of the current configuration.
+func (s *Store[T]) Get() T {
+	return *s.value.Load()
+}
+
+// Reload loads the configuration again and swaps it in on success.
+// On failure the current configuration is kept.
+func (s *Store[T]) Reload() error {
+	_, err := s.reload()
+	return err
+}
+
+// Watch reloads the store whenever one of the loaded config files changes,
+// see Watch. The returned stop function tears down the watcher.
+func (s *Store[T]) Watch(onChange func(error)) (func() error, error) {
+	s.mu.Lock()
+	files := s.files
+	s.mu.Unlock()
+
+	return startWatcher(files, s.reload, onChange)
+}
+
+func (s *Store[T]) reload() ([]string, error) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	cfg := new(T)
+	p, err := load(context.Background(), cfg, loadFromFile, s.paramsActions)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.files = p.loadedFiles
+	s.mu.Unlock()
+
+	s.value.Store(cfg)
+	return p.loadedFiles, nil
+}
@@ -0,0 +1,147 @@
+package cfg
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(file, []byte("server:\n  port: 1000\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	store, err := NewStore[TestConfig](
+		WithPaths(dir),
+		WithEnv(map[string]string{}),
+	)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+
+	if port := store.Get().Server.Port; port != 1000 {
+		t.Fatalf("Expected server.port 1000, got %d", port)
+	}
+
+	if err := os.WriteFile(file, []byte("server:\n  port: 2000\n"), 0o600); err != nil {
+		t.Fatalf("Failed to rewrite config: %v", err)
+	}
+
+	// Чтение во время перезагрузки не должно приводить к гонке
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = store.Get().Server.Port
+			}
+		}()
+	}
+
+	if err := store.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	wg.Wait()
+
+	if port := store.Get().Server.Port; port != 2000 {
+		t.Errorf("Expected server.port 2000 after reload, got %d", port)
+	}
+
+	if err := os.WriteFile(file, []byte("server: [broken\n"), 0o600); err != nil {
+		t.Fatalf("Failed to rewrite config: %v", err)
+	}
+
+	if err := store.Reload(); err == nil {
+		t.Error("Expected reload error for broken config")
+	}
+
+	if port := store.Get().Server.Port; port != 2000 {
+		t.Errorf("Expected server.port 2000 to survive broken reload, got %d", port)
+	}
+}
+
+func TestStoreReloadSerialized(t *testing.T) {
+	type Config struct {
+		Generation int64 `yaml:"generation"`
+	}
+
+	var inFlight, overlaps, generation atomic.Int64
+	store, err := NewStore[Config](
+		WithoutFile(),
+		WithEnv(map[string]string{}),
+		WithDefaulter(func(c interface{}) {
+			if inFlight.Add(1) > 1 {
+				overlaps.Add(1)
+			}
+			defer inFlight.Add(-1)
+			time.Sleep(time.Millisecond)
+			c.(*Config).Generation = generation.Add(1)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+
+	// Ручные перезагрузки из разных горутин не должны пересекаться
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := store.Reload(); err != nil {
+				t.Errorf("Reload failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := overlaps.Load(); n != 0 {
+		t.Errorf("Expected reloads to be serialized, got %d overlaps", n)
+	}
+
+	// Последняя загрузка и есть текущее значение
+	if got := store.Get().Generation; got != generation.Load() {
+		t.Errorf("Expected generation %d, got %d", generation.Load(), got)
+	}
+}
+
+func TestStoreWatch(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(file, []byte("server:\n  port: 1000\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	store, err := NewStore[TestConfig](
+		WithPaths(dir),
+		WithEnv(map[string]string{}),
+	)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+
+	changes := make(chan reloadResult, 10)
+	stop, err := store.Watch(func(err error) {
+		changes <- reloadResult{err: err, port: store.Get().Server.Port}
+	})
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	defer func() {
+		_ = stop()
+	}()
+
+	if err := os.WriteFile(file, []byte("server:\n  port: 3000\n"), 0o600); err != nil {
+		t.Fatalf("Failed to rewrite config: %v", err)
+	}
+
+	waitForReload(t, changes, func(r reloadResult) bool {
+		return r.err == nil && r.port == 3000
+	})
+}
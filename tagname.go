@@ -0,0 +1,112 @@
+package cfg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// renameYamlKeys rewrites mapping keys named by the WithTagName tag (or the json
+// tag with WithJSONTagFallback) into the keys yaml.v3 expects for type t, since the
+// decoder only reads the "yaml" tag.
+func renameYamlKeys(data []byte, t reflect.Type, params *parameters) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&node); err != nil {
+		if errors.Is(err, io.EOF) {
+			return data, nil
+		}
+		return nil, err
+	}
+
+	renameNodeKeys(&node, t, params)
+
+	renamed, err := yaml.Marshal(&node)
+	if err != nil {
+		return nil, fmt.Errorf("rename keys: %w", err)
+	}
+	return renamed, nil
+}
+
+func renameNodeKeys(node *yaml.Node, t reflect.Type, params *parameters) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			renameNodeKeys(child, t, params)
+		}
+	case yaml.SequenceNode:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for _, child := range node.Content {
+				renameNodeKeys(child, t.Elem(), params)
+			}
+		}
+	case yaml.MappingNode:
+		if t.Kind() == reflect.Map {
+			for i := 1; i < len(node.Content); i += 2 {
+				renameNodeKeys(node.Content[i], t.Elem(), params)
+			}
+			return
+		}
+		if t.Kind() != reflect.Struct {
+			return
+		}
+
+		fields := make(map[string]reflect.StructField)
+		collectTaggedFields(t, params, fields)
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			field, ok := fields[node.Content[i].Value]
+			if !ok {
+				continue
+			}
+			node.Content[i].Value = fieldKey(field)
+			renameNodeKeys(node.Content[i+1], field.Type, params)
+		}
+	}
+}
+
+// collectTaggedFields indexes fields of t by their key in the file. Fields of
+// embedded structs inlined by yaml.v3 are indexed as if declared on t.
+func collectTaggedFields(t reflect.Type, params *parameters, fields map[string]reflect.StructField) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if isInlined(field) {
+			collectTaggedFields(field.Type, params, fields)
+			continue
+		}
+
+		name := taggedKey(field, params)
+		if name == "-" {
+			continue
+		}
+		fields[name] = field
+	}
+}
+
+// isInlined reports whether field is an embedded struct inlined by yaml.v3. Only the
+// yaml tag can inline, since files are always decoded by yaml.v3 after renaming keys.
+func isInlined(field reflect.StructField) bool {
+	_, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	return field.Anonymous && field.Type.Kind() == reflect.Struct && hasTagOption(opts, "inline")
+}
+
+// taggedKey returns the key of field in the file.
+func taggedKey(field reflect.StructField, params *parameters) string {
+	// untagged fields keep the yaml key, so nested tagged structs are still renamed
+	name, _, _ := strings.Cut(field.Tag.Get(params.yamlTag), ",")
+	if name == "" && params.jsonTagFallback {
+		name, _, _ = strings.Cut(field.Tag.Get("json"), ",")
+	}
+	if name == "" {
+		name = fieldKey(field)
+	}
+	return name
+}
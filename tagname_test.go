@@ -0,0 +1,159 @@
+package cfg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWithTagName(t *testing.T) {
+	type Service struct {
+		Port int    `config:"listen_port"`
+		Host string `config:"hostname" cfgenv:"SERVICE_HOST"`
+	}
+	type Config struct {
+		Service Service  `config:"service"`
+		Labels  []string `config:"labels" cfgenv:"LABELS"`
+	}
+
+	var config Config
+	err := Load(&config,
+		WithPaths("./test"),
+		WithName("tagged_config"),
+		WithEnvPrefix("TEST"),
+		WithTagName("config", "cfgenv"),
+		WithStrict(),
+		WithEnv(map[string]string{
+			"TEST_SERVICE_HOST": "env-host",
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if config.Service.Port != 9090 {
+		t.Errorf("Expected service.listen_port 9090, got %d", config.Service.Port)
+	}
+
+	if config.Service.Host != "env-host" {
+		t.Errorf("Expected service.hostname env-host from env, got %s", config.Service.Host)
+	}
+
+	if !reflect.DeepEqual(config.Labels, []string{"a", "b"}) {
+		t.Errorf("Expected labels [a b], got %v", config.Labels)
+	}
+}
+
+func TestWithTagNameIgnoresDefaultEnvTag(t *testing.T) {
+	type Config struct {
+		Host string `yaml:"host" env:"HOST"`
+	}
+
+	var config Config
+	err := Load(&config,
+		WithPaths("./nonexistent"),
+		WithEnvPrefix("TEST"),
+		WithTagName("", "cfgenv"),
+		WithEnv(map[string]string{"TEST_HOST": "env-host"}),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if config.Host != "" {
+		t.Errorf("Expected env tag to be ignored, got host %s", config.Host)
+	}
+}
+
+func TestWithJSONTagFallback(t *testing.T) {
+	type Limits struct {
+		MaxConnections int `json:"maxConnections"`
+	}
+	type Config struct {
+		ServiceName string `json:"serviceName"`
+		Limits      Limits `json:"limits"`
+		Region      string `json:"regionName" yaml:"region"`
+		Ignored     string `json:"-"`
+	}
+
+	data := "serviceName: api\nlimits:\n  maxConnections: 100\nregion: eu\n"
+
+	var config Config
+	err := LoadReader(&config, strings.NewReader(data),
+		WithJSONTagFallback(),
+		WithStrict(),
+		WithEnv(map[string]string{}),
+	)
+	if err != nil {
+		t.Fatalf("LoadReader failed: %v", err)
+	}
+
+	if config.ServiceName != "api" || config.Limits.MaxConnections != 100 {
+		t.Errorf("Expected keys from json tags, got %+v", config)
+	}
+
+	// yaml тег важнее json
+	if config.Region != "eu" {
+		t.Errorf("Expected region from yaml tag, got '%s'", config.Region)
+	}
+
+	var withoutFallback Config
+	err = LoadReader(&withoutFallback, strings.NewReader(data), WithEnv(map[string]string{}))
+	if err != nil {
+		t.Fatalf("LoadReader failed: %v", err)
+	}
+
+	if withoutFallback.ServiceName != "" {
+		t.Errorf("Expected json tags to be ignored without the option, got '%s'", withoutFallback.ServiceName)
+	}
+}
+
+type testTaggedBackend struct {
+	Bucket string `config:"bucket_name"`
+}
+
+func (b *testTaggedBackend) Name() string { return "tagged:" + b.Bucket }
+
+func TestWithTagNameOverridesValidationAndVariants(t *testing.T) {
+	iface := reflect.TypeOf((*testBackend)(nil)).Elem()
+	RegisterVariant(iface, "tagged", func() any { return &testTaggedBackend{} })
+	t.Cleanup(func() { RegisterVariant(iface, "tagged", nil) })
+
+	type Service struct {
+		Port  int    `config:"listen_port"`
+		Owner string `config:"owner_name" required:"true"`
+	}
+	type Config struct {
+		Service Service     `config:"service"`
+		Storage testBackend `config:"storage"`
+	}
+
+	data := `
+service:
+  listen_port: 9090
+storage:
+  type: tagged
+  bucket_name: logs
+`
+
+	var config Config
+	err := LoadReader(&config, strings.NewReader(data),
+		WithTagName("config", "cfgenv"),
+		WithStrict(),
+		WithEnv(map[string]string{}),
+		WithOverrides([]string{"service.listen_port=8080"}),
+	)
+
+	// Путь в ошибке валидации совпадает с ключами файла
+	if err == nil || !strings.Contains(err.Error(), "service.owner_name is required") {
+		t.Fatalf("Expected error for service.owner_name, got %v", err)
+	}
+
+	if config.Service.Port != 8080 {
+		t.Errorf("Expected override of service.listen_port to 8080, got %d", config.Service.Port)
+	}
+
+	if config.Storage == nil || config.Storage.Name() != "tagged:logs" {
+		t.Errorf("Expected storage variant tagged:logs, got %v", config.Storage)
+	}
+}
@@ -0,0 +1,138 @@
+package cfg
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Update edits the yaml file at path in place: the file is decoded into a yaml.Node,
+// passed to mutate and written back with its comments and key order. The mapping
+// indentation of the file is kept, but the layout is otherwise re-encoded by yaml.v3
+// (e.g. "- x" under a key gets indented). An empty or comment-only file is passed as
+// a document with an empty mapping, and its comments are kept. Files with more than
+// one document are refused. The file is replaced atomically and keeps its
+// permissions; nothing is written when mutate fails.
+func Update(path string, mutate func(node *yaml.Node) error) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unread file %s: %w", path, err)
+	}
+
+	node, err := decodeSingleDocument(data)
+	if err != nil {
+		return &ParseError{File: path, Format: "yaml", Err: err}
+	}
+
+	if err := mutate(node); err != nil {
+		return fmt.Errorf("update %s: %w", path, err)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(detectIndent(data))
+	if err := encoder.Encode(node); err != nil {
+		return fmt.Errorf("marshal %s: %w", path, err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("marshal %s: %w", path, err)
+	}
+
+	return writeFileAtomic(path, buf.Bytes())
+}
+
+// decodeSingleDocument decodes the only yaml document of data. Empty data has no
+// document at all, so an empty mapping is returned instead, carrying the comments
+// of data that yaml.v3 would otherwise drop.
+func decodeSingleDocument(data []byte) (*yaml.Node, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+
+	var node yaml.Node
+	if err := decoder.Decode(&node); err != nil {
+		if !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		return &yaml.Node{
+			Kind:        yaml.DocumentNode,
+			HeadComment: commentLines(data),
+			Content:     []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}},
+		}, nil
+	}
+
+	var next yaml.Node
+	if err := decoder.Decode(&next); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return nil, err
+		}
+		return nil, errors.New("multi-document files are not supported")
+	}
+	return &node, nil
+}
+
+// commentLines returns the comment lines of data joined by newlines.
+func commentLines(data []byte) string {
+	var comments []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); strings.HasPrefix(line, "#") {
+			comments = append(comments, line)
+		}
+	}
+	return strings.Join(comments, "\n")
+}
+
+// detectIndent returns the indentation of the first nested mapping key in data,
+// 2 when data has none.
+func detectIndent(data []byte) int {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimLeft(line, " ")
+		if indent := len(line) - len(trimmed); indent > 0 && trimmed != "" &&
+			!strings.HasPrefix(trimmed, "#") && !strings.HasPrefix(trimmed, "-") {
+			return indent
+		}
+	}
+	return 2
+}
+
+// writeFileAtomic replaces path with data through a temporary file in the same
+// directory, so readers (and Watch) never see a partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", path, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
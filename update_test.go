@@ -0,0 +1,201 @@
+package cfg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// setPort заменяет значение server.port в документе
+func setPort(port string) func(node *yaml.Node) error {
+	return func(node *yaml.Node) error {
+		root := node.Content[0]
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			if root.Content[i].Value != "server" {
+				continue
+			}
+			server := root.Content[i+1]
+			for j := 0; j+1 < len(server.Content); j += 2 {
+				if server.Content[j].Value == "port" {
+					server.Content[j+1].Value = port
+					return nil
+				}
+			}
+		}
+		return errors.New("server.port not found")
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	original := "# Application config\nserver:\n    host: localhost # bind address\n    port: 3000\n"
+	if err := os.WriteFile(file, []byte(original), 0o640); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	if err := Update(file, setPort("9090")); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+
+	// Комментарии и отступы сохраняются
+	expected := "# Application config\nserver:\n    host: localhost # bind address\n    port: 9090\n"
+	if string(data) != expected {
+		t.Errorf("Expected %q, got %q", expected, string(data))
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatalf("Failed to stat config: %v", err)
+	}
+	if info.Mode().Perm() != 0o640 {
+		t.Errorf("Expected mode 0640, got %v", info.Mode().Perm())
+	}
+
+	var cfg TestConfig
+	if err := Load(&cfg, WithConfigPathEnv("TEST_CONFIG_FILE"), WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_CONFIG_FILE": file})); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Server.Port != 9090 {
+		t.Errorf("Expected server.port 9090, got %d", cfg.Server.Port)
+	}
+}
+
+func TestUpdateMutateError(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	original := "app:\n  name: untouched\n"
+	if err := os.WriteFile(file, []byte(original), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	err := Update(file, setPort("9090"))
+	if err == nil || !strings.Contains(err.Error(), "server.port not found") {
+		t.Fatalf("Expected mutate error, got %v", err)
+	}
+
+	// При ошибке файл не меняется и временные файлы не остаются
+	data, _ := os.ReadFile(file)
+	if string(data) != original {
+		t.Errorf("Expected file to be unchanged, got %q", string(data))
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected only config.yaml in dir, got %d entries", len(entries))
+	}
+}
+
+func TestUpdateEmptyFile(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	err := Update(file, func(node *yaml.Node) error {
+		node.Content[0].Content = append(node.Content[0].Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "debug"},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "true"},
+		)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(file)
+	if string(data) != "debug: true\n" {
+		t.Errorf("Expected %q, got %q", "debug: true\n", string(data))
+	}
+}
+
+func TestUpdateErrors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	noop := func(node *yaml.Node) error { return nil }
+
+	if err := Update(filepath.Join(dir, "missing.yaml"), noop); err == nil {
+		t.Error("Expected error for missing file")
+	}
+
+	broken := filepath.Join(dir, "broken.yaml")
+	if err := os.WriteFile(broken, []byte("server: [broken\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	var parseErr *ParseError
+	if err := Update(broken, noop); !errors.As(err, &parseErr) {
+		t.Errorf("Expected ParseError, got %v", err)
+	}
+}
+
+func TestUpdateCommentOnlyFile(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(file, []byte("# Managed by ops\n# do not edit\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	err := Update(file, func(node *yaml.Node) error {
+		node.Content[0].Content = append(node.Content[0].Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "debug"},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "true"},
+		)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	// Комментарии пустого документа не теряются
+	data, _ := os.ReadFile(file)
+	expected := "# Managed by ops\n# do not edit\n\ndebug: true\n"
+	if string(data) != expected {
+		t.Errorf("Expected %q, got %q", expected, string(data))
+	}
+}
+
+func TestUpdateMultiDocument(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	original := "server:\n  port: 3000\n---\nserver:\n  port: 4000\n"
+	if err := os.WriteFile(file, []byte(original), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	called := false
+	err := Update(file, func(node *yaml.Node) error {
+		called = true
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "multi-document") {
+		t.Fatalf("Expected multi-document error, got %v", err)
+	}
+	if called {
+		t.Error("Expected mutate not to be called")
+	}
+
+	// Второй документ не должен потеряться
+	data, _ := os.ReadFile(file)
+	if string(data) != original {
+		t.Errorf("Expected file to be unchanged, got %q", string(data))
+	}
+}
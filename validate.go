@@ -0,0 +1,200 @@
+package cfg
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func validateTags(cfg any, params *parameters) error {
+	v := reflect.ValueOf(cfg).Elem()
+	return errors.Join(validateStructTags(v, params, "")...)
+}
+
+// validateStructTags collects an error for every field tagged required:"true"
+// that still holds its zero value, every number outside its min/max tags and
+// every value missing from its oneof tag.
+func validateStructTags(v reflect.Value, params *parameters, path string) []error {
+	t := v.Type()
+	var errs []error
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		structField := t.Field(i)
+
+		if structField.Anonymous && field.Kind() == reflect.Struct {
+			errs = append(errs, validateStructTags(field, params, path)...)
+			continue
+		}
+
+		if !field.CanSet() {
+			continue
+		}
+
+		fieldPath := joinFieldPath(path, taggedKey(structField, params))
+
+		if structField.Tag.Get("required") == "true" && field.IsZero() {
+			errs = append(errs, fmt.Errorf("%s is %w", fieldPath, ErrRequired))
+			continue
+		}
+
+		switch {
+		case field.Kind() == reflect.Struct:
+			errs = append(errs, validateStructTags(field, params, fieldPath)...)
+		case field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.Struct && !field.IsNil():
+			errs = append(errs, validateStructTags(field.Elem(), params, fieldPath)...)
+		default:
+			if err := validateRange(field, structField, fieldPath); err != nil {
+				errs = append(errs, err)
+			}
+			if err := validateOneOf(field, structField, params, fieldPath); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateRange checks a numeric field against its min and max tags. Pointers
+// are checked when set, time.Duration bounds are durations like "1s".
+func validateRange(field reflect.Value, structField reflect.StructField, path string) error {
+	minTag, hasMin := structField.Tag.Lookup("min")
+	maxTag, hasMax := structField.Tag.Lookup("max")
+	if !hasMin && !hasMax {
+		return nil
+	}
+
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return nil
+		}
+		field = field.Elem()
+	}
+
+	if hasMin {
+		cmp, err := compareNumber(field, minTag)
+		if err != nil {
+			return fmt.Errorf("%s: invalid min tag %q: %w", path, minTag, err)
+		}
+		if cmp < 0 {
+			return fmt.Errorf("%s must be >= %s (got %v)", path, minTag, field.Interface())
+		}
+	}
+
+	if hasMax {
+		cmp, err := compareNumber(field, maxTag)
+		if err != nil {
+			return fmt.Errorf("%s: invalid max tag %q: %w", path, maxTag, err)
+		}
+		if cmp > 0 {
+			return fmt.Errorf("%s must be <= %s (got %v)", path, maxTag, field.Interface())
+		}
+	}
+
+	return nil
+}
+
+// validateOneOf checks that the field, or every element of a slice field, is one
+// of the space separated values of its oneof tag. Zero values are left to the
+// required tag, pointers are checked when set.
+func validateOneOf(field reflect.Value, structField reflect.StructField, params *parameters, path string) error {
+	tag, ok := structField.Tag.Lookup("oneof")
+	if !ok {
+		return nil
+	}
+
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return nil
+		}
+		field = field.Elem()
+	}
+
+	if field.IsZero() {
+		return nil
+	}
+
+	allowed := strings.Fields(tag)
+	values := []reflect.Value{field}
+	if field.Kind() == reflect.Slice || field.Kind() == reflect.Array {
+		values = values[:0]
+		for i := 0; i < field.Len(); i++ {
+			values = append(values, field.Index(i))
+		}
+	}
+
+	for _, value := range values {
+		s := fmt.Sprint(value.Interface())
+		if !containsValue(allowed, s, params.oneOfIgnoreCase) {
+			return fmt.Errorf("%s must be one of [%s] (got %q)", path, strings.Join(allowed, ", "), s)
+		}
+	}
+
+	return nil
+}
+
+func containsValue(allowed []string, value string, ignoreCase bool) bool {
+	for _, a := range allowed {
+		if a == value || ignoreCase && strings.EqualFold(a, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// compareNumber compares the numeric field with bound parsed for its kind and
+// returns -1, 0 or 1.
+func compareNumber(field reflect.Value, bound string) (int, error) {
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(bound)
+		if err != nil {
+			return 0, err
+		}
+		return cmp.Compare(field.Int(), int64(d)), nil
+	}
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(bound, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return cmp.Compare(field.Int(), n), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(bound, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return cmp.Compare(field.Uint(), n), nil
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(bound, 64)
+		if err != nil {
+			return 0, err
+		}
+		return cmp.Compare(field.Float(), n), nil
+	default:
+		return 0, fmt.Errorf("unsupported type: %s", field.Kind())
+	}
+}
+
+// fieldKey returns the config key of the field: the yaml tag name or
+// the lowercased field name, as yaml.v3 does.
+func fieldKey(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
+
+func joinFieldPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,315 @@
+package cfg
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequiredFields(t *testing.T) {
+	t.Parallel()
+
+	type RequiredConfig struct {
+		Name   string `yaml:"name" required:"true"`
+		Server struct {
+			Host string `yaml:"host" required:"true"`
+			Port int    `yaml:"port" env:"SERVER_PORT" required:"true"`
+		} `yaml:"server"`
+		Database struct {
+			Password string `yaml:"password" required:"true"`
+		} `yaml:"database"`
+	}
+
+	var cfg RequiredConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_SERVER_PORT": "8080"}),
+	)
+
+	if err == nil {
+		t.Fatal("Expected error for missing required fields")
+	}
+
+	for _, expected := range []string{
+		"name is required",
+		"server.host is required",
+		"database.password is required",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected error to contain '%s', got: %v", expected, err)
+		}
+	}
+
+	// Порт задан через env - требование выполнено
+	if strings.Contains(err.Error(), "server.port") {
+		t.Errorf("Expected server.port to be satisfied by env, got: %v", err)
+	}
+}
+
+func TestRequiredFieldsSatisfiedByYaml(t *testing.T) {
+	t.Parallel()
+
+	var cfg struct {
+		Server struct {
+			Host string `yaml:"host" required:"true"`
+			Port int    `yaml:"port" required:"true"`
+		} `yaml:"server"`
+	}
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("config"),
+		WithEnv(map[string]string{}),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+}
+
+func TestWithValidator(t *testing.T) {
+	t.Parallel()
+
+	type TLSConfig struct {
+		Enabled  bool   `yaml:"enabled" env:"TLS_ENABLED"`
+		CertFile string `yaml:"cert_file" env:"TLS_CERT_FILE"`
+	}
+
+	var calls []string
+	certRequired := func(cfg any) error {
+		calls = append(calls, "cert")
+		c := cfg.(*TLSConfig)
+		if c.Enabled && c.CertFile == "" {
+			return errors.New("cert_file must be set when TLS is enabled")
+		}
+		return nil
+	}
+	never := func(cfg any) error {
+		calls = append(calls, "never")
+		return nil
+	}
+
+	var cfg TLSConfig
+
+	err := Load(&cfg,
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_TLS_ENABLED": "true"}),
+		WithValidator(certRequired),
+		WithValidator(never),
+	)
+
+	if err == nil || !strings.Contains(err.Error(), "cert_file must be set") {
+		t.Fatalf("Expected validator error, got: %v", err)
+	}
+
+	if !reflect.DeepEqual(calls, []string{"cert"}) {
+		t.Errorf("Expected validation to stop at first error, got calls %v", calls)
+	}
+
+	calls = nil
+	err = Load(&cfg,
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_TLS_ENABLED": "true", "TEST_TLS_CERT_FILE": "cert.pem"}),
+		WithValidator(certRequired),
+		WithValidator(never),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(calls, []string{"cert", "never"}) {
+		t.Errorf("Expected validators to run in order, got calls %v", calls)
+	}
+}
+
+func TestMinMaxFields(t *testing.T) {
+	t.Parallel()
+
+	type RangeConfig struct {
+		Server struct {
+			Port    int           `yaml:"port" env:"SERVER_PORT" min:"1" max:"65535"`
+			Workers uint          `yaml:"workers" env:"WORKERS" min:"1"`
+			Timeout time.Duration `yaml:"timeout" env:"TIMEOUT" max:"1m"`
+		} `yaml:"server"`
+		Ratio float64 `yaml:"ratio" env:"RATIO" min:"0" max:"1"`
+		Limit *int    `yaml:"limit" env:"LIMIT" max:"10"`
+	}
+
+	var cfg RangeConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{
+			"TEST_SERVER_PORT": "70000",
+			"TEST_WORKERS":     "0",
+			"TEST_TIMEOUT":     "2m",
+			"TEST_RATIO":       "0.5",
+			"TEST_LIMIT":       "11",
+		}),
+	)
+
+	if err == nil {
+		t.Fatal("Expected error for out of range fields")
+	}
+
+	for _, expected := range []string{
+		"server.port must be <= 65535 (got 70000)",
+		"server.workers must be >= 1 (got 0)",
+		"server.timeout must be <= 1m (got 2m0s)",
+		"limit must be <= 10 (got 11)",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected error to contain '%s', got: %v", expected, err)
+		}
+	}
+
+	if strings.Contains(err.Error(), "ratio") {
+		t.Errorf("Expected ratio to be in range, got: %v", err)
+	}
+
+	var valid RangeConfig
+
+	err = Load(&valid,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_SERVER_PORT": "8080", "TEST_WORKERS": "4"}),
+	)
+
+	if err != nil {
+		t.Errorf("Expected values in range to pass, got: %v", err)
+	}
+}
+
+func TestOneOfFields(t *testing.T) {
+	t.Parallel()
+
+	type EnumConfig struct {
+		LogLevel string   `yaml:"log_level" env:"LOG_LEVEL" oneof:"debug info warn error"`
+		Mode     string   `yaml:"mode" env:"MODE" oneof:"fast safe"`
+		Regions  []string `yaml:"regions" env:"REGIONS" oneof:"eu us"`
+		Port     int      `yaml:"port" env:"PORT" oneof:"80 443"`
+	}
+
+	var cfg EnumConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{
+			"TEST_LOG_LEVEL": "INFO",
+			"TEST_REGIONS":   "eu,asia",
+			"TEST_PORT":      "443",
+		}),
+	)
+
+	if err == nil {
+		t.Fatal("Expected error for values outside oneof")
+	}
+
+	for _, expected := range []string{
+		`log_level must be one of [debug, info, warn, error] (got "INFO")`,
+		`regions must be one of [eu, us] (got "asia")`,
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected error to contain '%s', got: %v", expected, err)
+		}
+	}
+
+	// Пустое значение и допустимое число не являются ошибкой
+	if strings.Contains(err.Error(), "mode") || strings.Contains(err.Error(), "port") {
+		t.Errorf("Expected mode and port to pass, got: %v", err)
+	}
+
+	var folded EnumConfig
+
+	err = Load(&folded,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_LOG_LEVEL": "INFO"}),
+		WithOneOfIgnoreCase(),
+	)
+
+	if err != nil {
+		t.Errorf("Expected INFO to match with WithOneOfIgnoreCase, got: %v", err)
+	}
+}
+
+func TestWithDefaulter(t *testing.T) {
+	t.Parallel()
+
+	type DerivedConfig struct {
+		Host string `yaml:"host" env:"HOST"`
+		Port int    `yaml:"port" env:"PORT"`
+		Addr string `yaml:"addr" env:"ADDR" required:"true"`
+	}
+
+	derive := func(c any) {
+		if c := c.(*DerivedConfig); c.Addr == "" && c.Host != "" {
+			c.Addr = c.Host + ":" + strconv.Itoa(c.Port)
+		}
+	}
+
+	var cfg DerivedConfig
+
+	err := Load(&cfg,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_HOST": "localhost", "TEST_PORT": "8080"}),
+		WithDefaulter(derive),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Addr != "localhost:8080" {
+		t.Errorf("Expected derived addr localhost:8080, got '%s'", cfg.Addr)
+	}
+
+	var explicit DerivedConfig
+
+	err = Load(&explicit,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnvPrefix("TEST"),
+		WithEnv(map[string]string{"TEST_HOST": "localhost", "TEST_ADDR": "0.0.0.0:80"}),
+		WithDefaulter(derive),
+	)
+
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if explicit.Addr != "0.0.0.0:80" {
+		t.Errorf("Expected explicit addr to be kept, got '%s'", explicit.Addr)
+	}
+
+	// Дефолтер выполняется до проверки required
+	var missing DerivedConfig
+
+	err = Load(&missing,
+		WithPaths("./test"),
+		WithName("missing"),
+		WithEnv(map[string]string{}),
+		WithDefaulter(derive),
+	)
+
+	if !errors.Is(err, ErrRequired) {
+		t.Errorf("Expected required error when nothing can be derived, got %v", err)
+	}
+}
@@ -0,0 +1,23 @@
+// Package validation integrates github.com/go-playground/validator with cfg.
+// It is a separate module, so the validator is a dependency only of programs importing it.
+package validation
+
+import (
+	"github.com/ev-kotov/cfg"
+	"github.com/go-playground/validator/v10"
+)
+
+var defaultValidate = validator.New(validator.WithRequiredStructEnabled())
+
+// WithValidate validates `validate:"..."` struct tags after loading.
+func WithValidate() cfg.Action {
+	return WithValidateUsing(defaultValidate)
+}
+
+// WithValidateUsing validates struct tags with the given validator instance,
+// e.g. one with custom validations registered.
+func WithValidateUsing(v *validator.Validate) cfg.Action {
+	return cfg.WithValidator(func(c any) error {
+		return v.Struct(c)
+	})
+}
@@ -0,0 +1,74 @@
+package validation
+
+import (
+	"github.com/ev-kotov/cfg"
+	"github.com/go-playground/validator/v10"
+	"strings"
+	"testing"
+)
+
+type testConfig struct {
+	Server struct {
+		Host string `yaml:"host" validate:"required,hostname"`
+		Port int    `yaml:"port" env:"SERVER_PORT" validate:"min=1,max=65535"`
+	} `yaml:"server"`
+	LogLevel string `yaml:"log_level" validate:"oneof=debug info warn error"`
+}
+
+func TestWithValidate(t *testing.T) {
+	var c testConfig
+
+	err := cfg.LoadReader(&c,
+		strings.NewReader("server:\n  host: localhost\n  port: 8080\nlog_level: info\n"),
+		cfg.WithEnv(map[string]string{}),
+		WithValidate(),
+	)
+
+	if err != nil {
+		t.Fatalf("LoadReader failed: %v", err)
+	}
+}
+
+func TestWithValidateErrors(t *testing.T) {
+	var c testConfig
+
+	err := cfg.LoadReader(&c,
+		strings.NewReader("server:\n  host: localhost\nlog_level: verbose\n"),
+		cfg.WithEnvPrefix("TEST"),
+		cfg.WithEnv(map[string]string{"TEST_SERVER_PORT": "70000"}),
+		WithValidate(),
+	)
+
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+
+	for _, expected := range []string{"Port", "LogLevel"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected error to mention '%s', got: %v", expected, err)
+		}
+	}
+}
+
+func TestWithValidateUsing(t *testing.T) {
+	v := validator.New()
+	if err := v.RegisterValidation("even", func(fl validator.FieldLevel) bool {
+		return fl.Field().Int()%2 == 0
+	}); err != nil {
+		t.Fatalf("Failed to register validation: %v", err)
+	}
+
+	var c struct {
+		Workers int `yaml:"workers" validate:"even"`
+	}
+
+	err := cfg.LoadReader(&c,
+		strings.NewReader("workers: 3\n"),
+		cfg.WithEnv(map[string]string{}),
+		WithValidateUsing(v),
+	)
+
+	if err == nil {
+		t.Error("Expected custom validation error")
+	}
+}
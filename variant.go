@@ -0,0 +1,240 @@
+package cfg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultVariantKey is the YAML key selecting the concrete type of an interface field.
+const defaultVariantKey = "type"
+
+var (
+	variantsMu sync.RWMutex
+	variants   = make(map[reflect.Type]map[string]func() any)
+)
+
+// RegisterVariant registers a concrete type for fields of the interface type iface:
+// when the discriminator key of the field's YAML section ("type", or the name in a
+// variant:"kind" tag) equals key, factory is called and the rest of the section is
+// decoded into the value it returns, usually a pointer to struct. The value must
+// implement iface. Registering nil removes the variant.
+func RegisterVariant(iface reflect.Type, key string, factory func() any) {
+	variantsMu.Lock()
+	defer variantsMu.Unlock()
+
+	if factory == nil {
+		delete(variants[iface], key)
+		return
+	}
+	if variants[iface] == nil {
+		variants[iface] = make(map[string]func() any)
+	}
+	variants[iface][key] = factory
+}
+
+func lookupVariant(iface reflect.Type, key string) (func() any, bool) {
+	variantsMu.RLock()
+	defer variantsMu.RUnlock()
+
+	factory, ok := variants[iface][key]
+	return factory, ok
+}
+
+// hasVariants reports whether t has interface fields with registered variants,
+// directly or in nested structs.
+func hasVariants(t reflect.Type) bool {
+	return typeHasVariants(t, make(map[reflect.Type]bool))
+}
+
+func typeHasVariants(t reflect.Type, seen map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() == reflect.Interface {
+		variantsMu.RLock()
+		defer variantsMu.RUnlock()
+		return len(variants[t]) > 0
+	}
+
+	if t.Kind() != reflect.Struct || seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		if typeHasVariants(t.Field(i).Type, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// variantField is a struct field addressed by its YAML key.
+type variantField struct {
+	value reflect.Value
+	field reflect.StructField
+}
+
+// decodeVariants decodes the sections of interface fields into their registered
+// concrete types and returns data without those sections, so the rest can be
+// decoded as usual.
+func decodeVariants(data []byte, v any, params *parameters) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return data, nil
+		}
+		return nil, err
+	}
+
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return data, nil
+	}
+
+	if err := extractVariants(doc.Content[0], reflect.ValueOf(v).Elem(), params, ""); err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(&doc)
+}
+
+func extractVariants(node *yaml.Node, v reflect.Value, params *parameters, path string) error {
+	fields := make(map[string]variantField)
+	collectVariantFields(v, params, fields)
+
+	content := node.Content[:0:0]
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		f, ok := fields[key.Value]
+		if !ok || !hasVariants(f.field.Type) {
+			content = append(content, key, value)
+			continue
+		}
+
+		fieldPath := joinFieldPath(path, f.field.Name)
+
+		switch {
+		case f.value.Kind() == reflect.Interface:
+			if err := decodeVariant(f, value, params, fieldPath); err != nil {
+				return err
+			}
+			// секция уже декодирована в конкретный тип, обычный декодер ее не увидит
+			continue
+		case value.Kind == yaml.MappingNode && isNestedStruct(f.value):
+			if err := extractVariants(value, f.value, params, fieldPath); err != nil {
+				return err
+			}
+		case value.Kind == yaml.MappingNode && isStructPtr(f.value):
+			if f.value.IsNil() {
+				f.value.Set(reflect.New(f.value.Type().Elem()))
+			}
+			if err := extractVariants(value, f.value.Elem(), params, fieldPath); err != nil {
+				return err
+			}
+		}
+		content = append(content, key, value)
+	}
+
+	node.Content = content
+	return nil
+}
+
+// collectVariantFields indexes the fields of v by their key in the file (see
+// WithTagName), fields of embedded structs inlined by yaml.v3 as if declared on v.
+func collectVariantFields(v reflect.Value, params *parameters, fields map[string]variantField) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if isInlined(field) {
+			collectVariantFields(v.Field(i), params, fields)
+			continue
+		}
+
+		if name := taggedKey(field, params); name != "-" && v.Field(i).CanSet() {
+			fields[name] = variantField{value: v.Field(i), field: field}
+		}
+	}
+}
+
+// decodeVariant picks the concrete type by the discriminator and decodes the
+// section without the discriminator into it.
+func decodeVariant(f variantField, node *yaml.Node, params *parameters, path string) error {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("field %s: expected a mapping with a variant key", path)
+	}
+
+	variantKey := f.field.Tag.Get("variant")
+	if variantKey == "" {
+		variantKey = defaultVariantKey
+	}
+
+	section := &yaml.Node{Kind: yaml.MappingNode}
+	discriminator := ""
+	found := false
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == variantKey {
+			discriminator, found = node.Content[i+1].Value, true
+			continue
+		}
+		section.Content = append(section.Content, node.Content[i], node.Content[i+1])
+	}
+
+	if !found {
+		return fmt.Errorf("field %s: missing variant key %q", path, variantKey)
+	}
+
+	factory, ok := lookupVariant(f.field.Type, discriminator)
+	if !ok {
+		return fmt.Errorf("field %s: unknown %s %q", path, variantKey, discriminator)
+	}
+
+	concrete := factory()
+	concreteValue := reflect.ValueOf(concrete)
+	if !concreteValue.IsValid() || !concreteValue.Type().Implements(f.field.Type) {
+		return fmt.Errorf("field %s: variant %q returned %T, which does not implement %s",
+			path, discriminator, concrete, f.field.Type)
+	}
+
+	target := concrete
+	if concreteValue.Kind() != reflect.Ptr {
+		// значение без указателя декодируем через копию и сохраняем ее
+		ptr := reflect.New(concreteValue.Type())
+		ptr.Elem().Set(concreteValue)
+		target = ptr.Interface()
+	}
+
+	// ключи секции именованы по WithTagName, декодер yaml.v3 читает только тег yaml
+	if params.yamlTag != "yaml" || params.jsonTagFallback {
+		renameNodeKeys(section, reflect.TypeOf(target), params)
+	}
+
+	data, err := yaml.Marshal(section)
+	if err != nil {
+		return err
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(params.strict)
+	if err := decoder.Decode(target); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("field %s: %w", path, err)
+	}
+
+	if concreteValue.Kind() == reflect.Ptr {
+		f.value.Set(concreteValue)
+	} else {
+		f.value.Set(reflect.ValueOf(target).Elem())
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package cfg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testBackend interface {
+	Name() string
+}
+
+type testS3Backend struct {
+	Bucket string `yaml:"bucket"`
+	Region string `yaml:"region"`
+}
+
+func (b *testS3Backend) Name() string { return "s3:" + b.Bucket }
+
+type testGCSBackend struct {
+	Bucket string `yaml:"bucket"`
+}
+
+func (b testGCSBackend) Name() string { return "gcs:" + b.Bucket }
+
+func registerTestBackends(t *testing.T) {
+	t.Helper()
+
+	iface := reflect.TypeOf((*testBackend)(nil)).Elem()
+	RegisterVariant(iface, "s3", func() any { return &testS3Backend{Region: "us-east-1"} })
+	RegisterVariant(iface, "gcs", func() any { return testGCSBackend{} })
+	RegisterVariant(iface, "broken", func() any { return "not a backend" })
+	t.Cleanup(func() {
+		for _, key := range []string{"s3", "gcs", "broken"} {
+			RegisterVariant(iface, key, nil)
+		}
+	})
+}
+
+func TestRegisterVariant(t *testing.T) {
+	registerTestBackends(t)
+
+	type Storage struct {
+		Primary testBackend `yaml:"primary"`
+		Archive testBackend `yaml:"archive" variant:"kind"`
+	}
+	type Config struct {
+		App     TestApp  `yaml:"app"`
+		Storage *Storage `yaml:"storage"`
+	}
+
+	data := `
+app:
+  name: variant-app
+storage:
+  primary:
+    type: s3
+    bucket: logs
+  archive:
+    kind: gcs
+    bucket: cold
+`
+
+	var cfg Config
+	if err := LoadReader(&cfg, strings.NewReader(data), WithStrict()); err != nil {
+		t.Fatalf("LoadReader failed: %v", err)
+	}
+
+	if cfg.App.Name != "variant-app" {
+		t.Errorf("Expected app.name variant-app, got %s", cfg.App.Name)
+	}
+
+	// Значения из фабрики служат значениями по умолчанию
+	s3, ok := cfg.Storage.Primary.(*testS3Backend)
+	if !ok || s3.Bucket != "logs" || s3.Region != "us-east-1" {
+		t.Errorf("Expected s3 backend logs in us-east-1, got %#v", cfg.Storage.Primary)
+	}
+
+	if cfg.Storage.Archive == nil || cfg.Storage.Archive.Name() != "gcs:cold" {
+		t.Errorf("Expected gcs backend cold, got %#v", cfg.Storage.Archive)
+	}
+}
+
+func TestRegisterVariantErrors(t *testing.T) {
+	registerTestBackends(t)
+
+	type Config struct {
+		Backend testBackend `yaml:"backend"`
+	}
+
+	tests := []struct {
+		data     string
+		expected string
+	}{
+		{"backend:\n  bucket: logs\n", `field Backend: missing variant key "type"`},
+		{"backend:\n  type: azure\n", `field Backend: unknown type "azure"`},
+		{"backend:\n  type: broken\n", "returned string, which does not implement"},
+		{"backend: s3\n", "field Backend: expected a mapping with a variant key"},
+		{"backend:\n  type: s3\n  buckt: logs\n", "field buckt not found"},
+	}
+
+	for _, tt := range tests {
+		var cfg Config
+		err := LoadReader(&cfg, strings.NewReader(tt.data), WithStrict())
+		if err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package cfg
+
+import (
+	"context"
+	"fmt"
+	"github.com/fsnotify/fsnotify"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Watch loads the configuration into cfg and reloads it whenever one of the loaded
+// config files changes. Every reload decodes into a fresh value and copies it into cfg
+// only on success, so a broken file keeps the previous values. The copy and the
+// onChange callback run under an internal lock, onChange receives the reload error
+// or nil. Code reading cfg concurrently with reloads must synchronize itself, see Store.
+// The returned stop function tears down the watcher.
+func Watch(cfg any, onChange func(error), paramsActions ...Action) (func() error, error) {
+	p, err := load(context.Background(), cfg, loadFromFile, paramsActions)
+	if err != nil {
+		return nil, err
+	}
+
+	reload := func() ([]string, error) {
+		fresh := reflect.New(reflect.TypeOf(cfg).Elem())
+		p, err := load(context.Background(), fresh.Interface(), loadFromFile, paramsActions)
+		if err != nil {
+			return nil, err
+		}
+		reflect.ValueOf(cfg).Elem().Set(fresh.Elem())
+		return p.loadedFiles, nil
+	}
+
+	return startWatcher(p.loadedFiles, reload, onChange)
+}
+
+// WatchChanges loads a configuration of type T and calls onChange with the previous
+// and the new value after the initial load (old is the zero value) and after every
+// successful reload, so consumers can react only to the fields they care about.
+// Reload errors are passed to onError, which may be nil; the previous value is kept.
+func WatchChanges[T any](onChange func(old, new T), onError func(error), paramsActions ...Action) (func() error, error) {
+	var current T
+	p, err := load(context.Background(), &current, loadFromFile, paramsActions)
+	if err != nil {
+		return nil, err
+	}
+
+	var zero T
+	onChange(zero, current)
+
+	reload := func() ([]string, error) {
+		var fresh T
+		p, err := load(context.Background(), &fresh, loadFromFile, paramsActions)
+		if err != nil {
+			return nil, err
+		}
+		old := current
+		current = fresh
+		onChange(old, fresh)
+		return p.loadedFiles, nil
+	}
+
+	return startWatcher(p.loadedFiles, reload, func(err error) {
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	})
+}
+
+// startWatcher watches files and calls reload on changes. reload returns the files
+// to watch from then on.
+func startWatcher(files []string, reload func() ([]string, error), onChange func(error)) (func() error, error) {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("create watcher: %w", err)
+	}
+
+	w := &watcher{
+		reloadFunc: reload,
+		onChange:   onChange,
+		fsWatcher:  fsWatcher,
+		files:      make(map[string]string),
+		dirs:       make(map[string]bool),
+		done:       make(chan struct{}),
+	}
+
+	if err := w.watch(files); err != nil {
+		_ = fsWatcher.Close()
+		return nil, err
+	}
+
+	w.wg.Add(1)
+	go w.run()
+
+	return w.stop, nil
+}
+
+type watcher struct {
+	mu         sync.Mutex
+	reloadFunc func() ([]string, error)
+	onChange   func(error)
+	fsWatcher  *fsnotify.Watcher
+	// files maps every watched file to the file its symlinks resolve to
+	files    map[string]string
+	dirs     map[string]bool
+	done     chan struct{}
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+	stopErr  error
+}
+
+// watch starts watching files. Parent directories are watched instead of the files
+// themselves: editors save by rename and Kubernetes updates ConfigMap mounts by
+// swapping a symlink, both replace the file a watch would be attached to.
+func (w *watcher) watch(files []string) error {
+	for _, file := range files {
+		file = filepath.Clean(file)
+		if _, ok := w.files[file]; ok {
+			continue
+		}
+
+		target := resolveTarget(file)
+		if err := w.watchDirs(file, target); err != nil {
+			return err
+		}
+		w.files[file] = target
+	}
+	return nil
+}
+
+// watchDirs watches the directories of file and of its symlink target.
+func (w *watcher) watchDirs(file, target string) error {
+	for _, dir := range []string{filepath.Dir(file), filepath.Dir(target)} {
+		if w.dirs[dir] {
+			continue
+		}
+		if err := w.fsWatcher.Add(dir); err != nil {
+			return fmt.Errorf("watch file %s: %w", file, err)
+		}
+		w.dirs[dir] = true
+	}
+	return nil
+}
+
+// changed reports whether the event on name affects a watched file: the file or
+// its target was written, or a symlink swap made the file resolve elsewhere.
+func (w *watcher) changed(name string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	name = filepath.Clean(name)
+	changed := false
+	for file, target := range w.files {
+		if name == file || name == target {
+			changed = true
+		}
+
+		if resolved := resolveTarget(file); resolved != target {
+			w.files[file] = resolved
+			// новая цель может лежать в другом каталоге, ошибка всплывет при перезагрузке
+			_ = w.watchDirs(file, resolved)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// resolveTarget resolves symlinks in file. A file missing in the middle of a swap
+// resolves to itself, so the next successful resolution is seen as a change.
+func resolveTarget(file string) string {
+	target, err := filepath.EvalSymlinks(file)
+	if err != nil {
+		return file
+	}
+	return target
+}
+
+// debounceDelay coalesces the burst of events a single save produces
+// (truncate + write, or rename + create), so a half-written file is not loaded.
+const debounceDelay = 100 * time.Millisecond
+
+func (w *watcher) run() {
+	defer w.wg.Done()
+
+	timer := time.NewTimer(debounceDelay)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case event, ok := <-w.fsWatcher.Events:
+			if !ok {
+				return
+			}
+			if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) &&
+				!event.Has(fsnotify.Rename) && !event.Has(fsnotify.Remove) {
+				continue
+			}
+
+			// В каталоге меняются и посторонние файлы - они не вызывают перезагрузку
+			if !w.changed(event.Name) {
+				continue
+			}
+
+			timer.Reset(debounceDelay)
+		case <-timer.C:
+			w.reload()
+		case err, ok := <-w.fsWatcher.Errors:
+			if !ok {
+				return
+			}
+			w.notify(fmt.Errorf("watch config: %w", err))
+		}
+	}
+}
+
+func (w *watcher) reload() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	files, err := w.reloadFunc()
+	if err == nil {
+		err = w.watch(files)
+	}
+
+	if w.onChange != nil {
+		w.onChange(err)
+	}
+}
+
+func (w *watcher) notify(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.onChange != nil {
+		w.onChange(err)
+	}
+}
+
+func (w *watcher) stop() error {
+	w.stopOnce.Do(func() {
+		close(w.done)
+		w.stopErr = w.fsWatcher.Close()
+		w.wg.Wait()
+	})
+	return w.stopErr
+}
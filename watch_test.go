@@ -0,0 +1,259 @@
+package cfg
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// reloadResult is captured inside the Watch callback, which runs under the watcher lock.
+type reloadResult struct {
+	err  error
+	port int
+}
+
+func TestWatchReloadsOnChange(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(file, []byte("server:\n  port: 1000\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	var cfg TestConfig
+	changes := make(chan reloadResult, 10)
+
+	stop, err := Watch(&cfg, func(err error) {
+		changes <- reloadResult{err: err, port: cfg.Server.Port}
+	},
+		WithPaths(dir),
+		WithEnv(map[string]string{}),
+	)
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	defer func() {
+		if err := stop(); err != nil {
+			t.Errorf("stop failed: %v", err)
+		}
+	}()
+
+	if cfg.Server.Port != 1000 {
+		t.Fatalf("Expected server.port 1000 after initial load, got %d", cfg.Server.Port)
+	}
+
+	if err := os.WriteFile(file, []byte("server:\n  port: 2000\n"), 0o600); err != nil {
+		t.Fatalf("Failed to rewrite config: %v", err)
+	}
+
+	waitForReload(t, changes, func(r reloadResult) bool {
+		return r.err == nil && r.port == 2000
+	})
+
+	// Некорректный файл не должен затирать последнюю рабочую конфигурацию
+	if err := os.WriteFile(file, []byte("server: [broken\n"), 0o600); err != nil {
+		t.Fatalf("Failed to rewrite config: %v", err)
+	}
+
+	waitForReload(t, changes, func(r reloadResult) bool {
+		return r.err != nil && r.port == 2000
+	})
+}
+
+func TestWatchSymlinkSwap(t *testing.T) {
+	dir := t.TempDir()
+
+	// Раскладка как у ConfigMap в Kubernetes: config.yaml -> ..data/config.yaml, ..data -> ..v1
+	writeVersion := func(version string, port string) {
+		t.Helper()
+		if err := os.Mkdir(filepath.Join(dir, version), 0o700); err != nil {
+			t.Fatalf("Failed to create version dir: %v", err)
+		}
+		data := []byte("server:\n  port: " + port + "\n")
+		if err := os.WriteFile(filepath.Join(dir, version, "config.yaml"), data, 0o600); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+	}
+
+	writeVersion("..v1", "1000")
+	if err := os.Symlink("..v1", filepath.Join(dir, "..data")); err != nil {
+		t.Skipf("Symlinks are not supported: %v", err)
+	}
+	if err := os.Symlink(filepath.Join("..data", "config.yaml"), filepath.Join(dir, "config.yaml")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	var cfg TestConfig
+	changes := make(chan reloadResult, 10)
+
+	stop, err := Watch(&cfg, func(err error) {
+		changes <- reloadResult{err: err, port: cfg.Server.Port}
+	},
+		WithPaths(dir),
+		WithEnv(map[string]string{}),
+	)
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	defer func() {
+		_ = stop()
+	}()
+
+	if cfg.Server.Port != 1000 {
+		t.Fatalf("Expected server.port 1000 after initial load, got %d", cfg.Server.Port)
+	}
+
+	// Атомарная подмена симлинка ..data, сам config.yaml не меняется
+	writeVersion("..v2", "2000")
+	if err := os.Symlink("..v2", filepath.Join(dir, "..data_tmp")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+	if err := os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")); err != nil {
+		t.Fatalf("Failed to swap symlink: %v", err)
+	}
+
+	waitForReload(t, changes, func(r reloadResult) bool {
+		return r.err == nil && r.port == 2000
+	})
+}
+
+func TestWatchRenameSave(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(file, []byte("server:\n  port: 1000\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	var cfg TestConfig
+	changes := make(chan reloadResult, 10)
+
+	stop, err := Watch(&cfg, func(err error) {
+		changes <- reloadResult{err: err, port: cfg.Server.Port}
+	},
+		WithPaths(dir),
+		WithEnv(map[string]string{}),
+	)
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	defer func() {
+		_ = stop()
+	}()
+
+	// Редактор сохраняет во временный файл и переименовывает его поверх исходного, дважды
+	for _, port := range []int{2000, 3000} {
+		tmp := filepath.Join(dir, "config.yaml.tmp")
+		data := []byte("server:\n  port: " + strconv.Itoa(port) + "\n")
+		if err := os.WriteFile(tmp, data, 0o600); err != nil {
+			t.Fatalf("Failed to write temp config: %v", err)
+		}
+		if err := os.Rename(tmp, file); err != nil {
+			t.Fatalf("Failed to rename config: %v", err)
+		}
+
+		waitForReload(t, changes, func(r reloadResult) bool {
+			return r.err == nil && r.port == port
+		})
+	}
+}
+
+func TestWatchStopIsIdempotent(t *testing.T) {
+	var cfg TestConfig
+
+	stop, err := Watch(&cfg, nil,
+		WithPaths("./test"),
+		WithName("config"),
+		WithEnv(map[string]string{}),
+	)
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+
+	if err := stop(); err != nil {
+		t.Errorf("stop failed: %v", err)
+	}
+
+	if err := stop(); err != nil {
+		t.Errorf("second stop failed: %v", err)
+	}
+}
+
+func TestWatchChanges(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(file, []byte("server:\n  port: 1000\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	type change struct {
+		old, new int
+	}
+	changes := make(chan change, 10)
+	errs := make(chan error, 10)
+
+	stop, err := WatchChanges(func(old, new TestConfig) {
+		changes <- change{old: old.Server.Port, new: new.Server.Port}
+	}, func(err error) {
+		errs <- err
+	},
+		WithPaths(dir),
+		WithEnv(map[string]string{}),
+	)
+	if err != nil {
+		t.Fatalf("WatchChanges failed: %v", err)
+	}
+	defer func() {
+		_ = stop()
+	}()
+
+	// Первая загрузка приходит с нулевым старым значением
+	if c := <-changes; c.old != 0 || c.new != 1000 {
+		t.Fatalf("Expected initial change 0 -> 1000, got %d -> %d", c.old, c.new)
+	}
+
+	if err := os.WriteFile(file, []byte("server:\n  port: 2000\n"), 0o600); err != nil {
+		t.Fatalf("Failed to rewrite config: %v", err)
+	}
+
+	select {
+	case c := <-changes:
+		if c.old != 1000 || c.new != 2000 {
+			t.Errorf("Expected change 1000 -> 2000, got %d -> %d", c.old, c.new)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for change")
+	}
+
+	if err := os.WriteFile(file, []byte("server: [broken\n"), 0o600); err != nil {
+		t.Fatalf("Failed to rewrite config: %v", err)
+	}
+
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Error("Expected reload error")
+		}
+	case c := <-changes:
+		t.Errorf("Expected no change for broken config, got %d -> %d", c.old, c.new)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for reload error")
+	}
+}
+
+// waitForReload waits for a reload result matching done.
+func waitForReload(t *testing.T, changes <-chan reloadResult, done func(reloadResult) bool) {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case r := <-changes:
+			if done(r) {
+				return
+			}
+		case <-timeout:
+			t.Fatal("Timed out waiting for reload")
+		}
+	}
+}